@@ -0,0 +1,202 @@
+package gocron
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// concurrencyLimiter limits the number of jobs running at the same time.
+// When all the slots are taken, the jobs waiting for one are served in the
+// order they were scheduled at, so that jobs scheduled often can't starve
+// the others
+type concurrencyLimiter struct {
+	mu      sync.Mutex
+	max     int
+	running int
+	seq     uint64
+	waiting waitQueue
+}
+
+func newConcurrencyLimiter(max int) *concurrencyLimiter {
+	return &concurrencyLimiter{max: max}
+}
+
+// acquire blocks until a slot is available for a job scheduled at scheduledAt
+func (l *concurrencyLimiter) acquire(scheduledAt time.Time) {
+	l.mu.Lock()
+	if l.running < l.max && len(l.waiting) == 0 {
+		l.running++
+		l.mu.Unlock()
+		return
+	}
+	w := &waiter{scheduledAt: scheduledAt, seq: l.seq, ready: make(chan struct{})}
+	l.seq++
+	heap.Push(&l.waiting, w)
+	l.mu.Unlock()
+	<-w.ready
+}
+
+// release frees a slot, handing it over to the longest waiting job if any
+func (l *concurrencyLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.waiting) > 0 {
+		close(heap.Pop(&l.waiting).(*waiter).ready)
+		return
+	}
+	l.running--
+}
+
+type waiter struct {
+	scheduledAt time.Time
+	seq         uint64 // breaks ties between jobs scheduled at the same time
+	ready       chan struct{}
+}
+
+// waitQueue is a min-heap of waiters ordered by scheduled time
+type waitQueue []*waiter
+
+func (q waitQueue) Len() int { return len(q) }
+
+func (q waitQueue) Less(i, j int) bool {
+	if q[i].scheduledAt.Equal(q[j].scheduledAt) {
+		return q[i].seq < q[j].seq
+	}
+	return q[i].scheduledAt.Before(q[j].scheduledAt)
+}
+
+func (q waitQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *waitQueue) Push(x interface{}) { *q = append(*q, x.(*waiter)) }
+
+func (q *waitQueue) Pop() interface{} {
+	old := *q
+	w := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return w
+}
+
+// resourcePool is the pool of a Job set with ResourcePool
+type resourcePool struct {
+	key string
+	max int
+}
+
+// ResourcePool limits the jobs sharing the pool key to max runs at the same
+// time, e.g. for the jobs using the same database, independently of
+// SetMaxConcurrentJobs. The runs waiting for the pool are served in the order
+// they were scheduled at. The jobs sharing a key should have the same max, the
+// pool being sized by the first run using it
+func (j *Job) ResourcePool(key string, max int) *Job {
+	j.Lock()
+	defer j.Unlock()
+	if key == "" || max <= 0 {
+		j.err = ErrInvalidResourcePool
+		return j
+	}
+	j.resourcePool = &resourcePool{key: key, max: max}
+	return j
+}
+
+func (j *Job) getResourcePool() *resourcePool {
+	j.RLock()
+	defer j.RUnlock()
+	return j.resourcePool
+}
+
+// poolLimiter returns the limiter of the pool, created on first use
+func (s *Scheduler) poolLimiter(pool *resourcePool) *concurrencyLimiter {
+	s.poolsMutex.Lock()
+	defer s.poolsMutex.Unlock()
+	if s.pools == nil {
+		s.pools = make(map[string]*concurrencyLimiter)
+	}
+	limiter, ok := s.pools[pool.key]
+	if !ok {
+		limiter = newConcurrencyLimiter(pool.max)
+		s.pools[pool.key] = limiter
+	}
+	return limiter
+}
+
+// workerPool runs the jobs on a fixed number of goroutines, set with
+// SetWorkerPool
+type workerPool struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	closed bool
+	queue  []func() // runs waiting for a worker
+}
+
+func newWorkerPool(size int) *workerPool {
+	p := &workerPool{}
+	p.cond = sync.NewCond(&p.mu)
+	for i := 0; i < size; i++ {
+		go p.work()
+	}
+	return p
+}
+
+func (p *workerPool) work() {
+	for {
+		p.mu.Lock()
+		for len(p.queue) == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		if len(p.queue) == 0 {
+			p.mu.Unlock()
+			return
+		}
+		run := p.queue[0]
+		p.queue[0] = nil
+		p.queue = p.queue[1:]
+		p.mu.Unlock()
+		run()
+	}
+}
+
+// submit queues the run without blocking, so that a run submitted by a
+// worker, e.g. of a triggered job, can't hold up the pool. It returns false
+// without queuing the run if the pool was closed
+func (p *workerPool) submit(run func()) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false
+	}
+	p.queue = append(p.queue, run)
+	p.cond.Signal()
+	return true
+}
+
+// close stops the workers once they ran the queued runs
+func (p *workerPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	p.cond.Broadcast()
+}
+
+// SetWorkerPool runs the jobs which aren't Sync on a pool of size workers
+// instead of a goroutine per run. The runs wait in a queue for a worker,
+// which neither the scheduler nor the runs submitting others block on, so
+// that no run is dropped. A size of 0 or less removes the pool, whose
+// workers stop once they ran the queued runs
+func (s *Scheduler) SetWorkerPool(size int) {
+	s.workersMutex.Lock()
+	defer s.workersMutex.Unlock()
+	if s.workers != nil {
+		s.workers.close()
+		s.workers = nil
+	}
+	if size > 0 {
+		s.workers = newWorkerPool(size)
+	}
+}
+
+func (s *Scheduler) getWorkerPool() *workerPool {
+	s.workersMutex.RLock()
+	defer s.workersMutex.RUnlock()
+	return s.workers
+}
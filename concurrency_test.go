@@ -0,0 +1,136 @@
+package gocron
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	l := newConcurrencyLimiter(1)
+	scheduledAt := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	l.acquire(scheduledAt)
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	wait := func(name string, scheduledAt time.Time) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.acquire(scheduledAt)
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			l.release()
+		}()
+		// let the goroutine reach the queue
+		time.Sleep(10 * time.Millisecond)
+	}
+	wait("frequent 1", scheduledAt.Add(10*time.Second))
+	wait("frequent 2", scheduledAt.Add(10*time.Second))
+	wait("frequent 3", scheduledAt.Add(20*time.Second))
+	wait("rare", scheduledAt.Add(-time.Hour))
+
+	l.release()
+	wg.Wait()
+	assert.Equal(t, []string{"rare", "frequent 1", "frequent 2", "frequent 3"}, order)
+	assert.Zero(t, l.running)
+}
+
+func TestJob_ResourcePool(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	release := make(chan struct{})
+	pooled, pooledStarted, pooledRuns, pooledMaxActive := slowJob(release)
+	first, err := s.Every(1).Second().Do(pooled)
+	require.NoError(t, err)
+	second, err := s.Every(2).Seconds().Do(pooled)
+	require.NoError(t, err)
+	first.ResourcePool("db", 1)
+	second.ResourcePool("db", 1)
+	unrelated, unrelatedStarted, unrelatedRuns, unrelatedMaxActive := slowJob(release)
+	other, err := s.Every(1).Second().Do(unrelated)
+	require.NoError(t, err)
+
+	require.NoError(t, s.run(first))
+	<-pooledStarted
+	require.NoError(t, s.run(second))
+	require.NoError(t, s.run(other))
+	require.NoError(t, s.run(other))
+	<-unrelatedStarted
+	<-unrelatedStarted
+	assert.Equal(t, int32(1), atomic.LoadInt32(pooledRuns), "the second job should wait for the pool")
+	assert.Equal(t, int32(2), atomic.LoadInt32(unrelatedMaxActive), "unrelated jobs should run freely")
+
+	close(release)
+	s.runningJobs.Wait()
+	assert.Equal(t, int32(2), atomic.LoadInt32(pooledRuns))
+	assert.Equal(t, int32(1), atomic.LoadInt32(pooledMaxActive))
+	assert.Equal(t, int32(2), atomic.LoadInt32(unrelatedRuns))
+
+	t.Run("invalid pool", func(t *testing.T) {
+		assert.Equal(t, ErrInvalidResourcePool, NewJob(1).ResourcePool("", 1).Err())
+		assert.Equal(t, ErrInvalidResourcePool, NewJob(1).ResourcePool("db", 0).Err())
+	})
+}
+
+func TestScheduler_SetWorkerPool(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	s.SetWorkerPool(2)
+	defer s.SetWorkerPool(0)
+	release := make(chan struct{})
+	f, started, runs, maxActive := slowJob(release)
+	job, err := s.Every(1).Second().Do(f)
+	require.NoError(t, err)
+	other, err := s.Every(1).Second().Do(f)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, s.run(job))
+		require.NoError(t, s.run(other))
+	}
+	<-started
+	<-started
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(runs), "the queued runs should wait for a worker")
+
+	close(release)
+	s.runningJobs.Wait()
+	assert.Equal(t, int32(4), atomic.LoadInt32(runs))
+	assert.Equal(t, int32(2), atomic.LoadInt32(maxActive))
+
+	t.Run("a job triggered from a worker", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		s.SetWorkerPool(1)
+		defer s.SetWorkerPool(0)
+		release := make(chan struct{})
+		f, started, _, _ := slowJob(release)
+		a, err := s.Every(1).Second().Do(f)
+		require.NoError(t, err)
+		var triggeredRuns int32
+		triggered, err := s.Every(1).Hour().Do(func() { atomic.AddInt32(&triggeredRuns, 1) })
+		require.NoError(t, err)
+		triggered.TriggerOnCompletionOf(a)
+
+		require.NoError(t, s.run(a))
+		<-started
+		require.NoError(t, s.run(a))
+		close(release)
+
+		done := make(chan struct{})
+		go func() {
+			s.runningJobs.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("the pool should not deadlock")
+		}
+		assert.Equal(t, int32(2), atomic.LoadInt32(&triggeredRuns))
+	})
+}
@@ -0,0 +1,157 @@
+package gocron
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ToCron renders the schedule of the Job as a standard five-field cron
+// expression, e.g. "0 9 * * 1-5" for a Job running at 09:00 on weekdays,
+// in the location of its Scheduler. Intervals of minutes and hours are
+// aligned to the clock, like cron runs them. It returns an error wrapping
+// ErrNotCronExpressible for the schedules cron can't express, e.g. with
+// seconds, jitter or an interval not dividing the hour, day or week
+func (j *Job) ToCron() (string, error) {
+	j.RLock()
+	defer j.RUnlock()
+	if reason := j.cronInexpressible(); reason != "" {
+		return "", fmt.Errorf("%w: %s", ErrNotCronExpressible, reason)
+	}
+	switch j.unit {
+	case seconds, minutes, hours:
+		return j.intervalToCron()
+	case days, weeks, months:
+		minute, hour, err := atTimesToCron(j.atTimes)
+		if err != nil {
+			return "", err
+		}
+		dayOfMonth, dayOfWeek := "*", "*"
+		switch {
+		case j.interval != 1:
+			return "", fmt.Errorf("%w: every %d %s", ErrNotCronExpressible, j.interval, j.unit)
+		case j.unit == weeks && len(j.scheduledWeekdays) == 0:
+			return "", fmt.Errorf("%w: weeks without weekdays", ErrNotCronExpressible)
+		case j.unit == weeks:
+			weekdays := make([]int, len(j.scheduledWeekdays))
+			for i, weekday := range j.scheduledWeekdays {
+				weekdays[i] = int(weekday)
+			}
+			dayOfWeek = cronList(weekdays)
+		case j.unit == months && (len(j.daysOfTheMonth) == 0 || j.daysOfTheMonth[0] < 1):
+			return "", fmt.Errorf("%w: months without days of the month", ErrNotCronExpressible)
+		case j.unit == months:
+			dayOfMonth = cronList(j.daysOfTheMonth)
+		}
+		return strings.Join([]string{minute, hour, dayOfMonth, "*", dayOfWeek}, " "), nil
+	}
+	return "", ErrPeriodNotSpecified
+}
+
+// cronInexpressible returns the part of the Job's schedule cron can't express,
+// or an empty string. The Job must be read locked
+func (j *Job) cronInexpressible() string {
+	switch {
+	case j.jitter > 0:
+		return "jitter"
+	case j.nextRunFunc != nil:
+		return "custom next run function"
+	case j.phase != nil:
+		return "phase"
+	case j.weekParity != anyWeek:
+		return "ISO week parity"
+	case len(j.weekdayIntervals) > 0:
+		return "weekday intervals"
+	case len(j.excludedDates) > 0:
+		return "excluded dates"
+	case j.dailyWindow != nil || len(j.blackoutWindows) > 0:
+		return "time windows"
+	case j.scheduleChange != nil:
+		return "schedule change"
+	}
+	return ""
+}
+
+// intervalToCron renders an interval of seconds, minutes or hours, which
+// must divide the hour or the day. The Job must be read locked
+func (j *Job) intervalToCron() (string, error) {
+	d, err := intervalDuration(j.interval, j.unit)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case d%time.Minute != 0:
+		return "", fmt.Errorf("%w: every %d %s", ErrNotCronExpressible, j.interval, j.unit)
+	case d < time.Hour && time.Hour%d == 0:
+		return cronStep(int(d/time.Minute)) + " * * * *", nil
+	case d%time.Hour == 0 && 24*time.Hour%d == 0:
+		return "0 " + cronStep(int(d/time.Hour)) + " * * *", nil
+	}
+	return "", fmt.Errorf("%w: every %d %s", ErrNotCronExpressible, j.interval, j.unit)
+}
+
+// atTimesToCron renders the times of the day as the minute and hour fields,
+// midnight if there are none. The times must be on whole minutes, and
+// combine each of their minutes with each of their hours
+func atTimesToCron(atTimes []time.Duration) (minute, hour string, err error) {
+	if len(atTimes) == 0 {
+		return "0", "0", nil
+	}
+	var minutes, hours []int
+	seen := make(map[time.Duration]bool, len(atTimes))
+	for _, atTime := range atTimes {
+		if atTime%time.Minute != 0 {
+			return "", "", fmt.Errorf("%w: time of the day with seconds", ErrNotCronExpressible)
+		}
+		seen[atTime] = true
+		minutes = appendUnique(minutes, int(atTime%time.Hour/time.Minute))
+		hours = appendUnique(hours, int(atTime/time.Hour))
+	}
+	if len(minutes)*len(hours) != len(seen) {
+		return "", "", fmt.Errorf("%w: times of the day not combining their hours and minutes", ErrNotCronExpressible)
+	}
+	sort.Ints(minutes)
+	sort.Ints(hours)
+	return cronList(minutes), cronList(hours), nil
+}
+
+func appendUnique(values []int, value int) []int {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// cronStep renders a step of n, "*" for 1
+func cronStep(n int) string {
+	if n == 1 {
+		return "*"
+	}
+	return "*/" + strconv.Itoa(n)
+}
+
+// cronList renders sorted values as a list, with ranges for the consecutive
+// ones, e.g. "1-5" or "1,15"
+func cronList(values []int) string {
+	parts := make([]string, 0, len(values))
+	for i := 0; i < len(values); {
+		end := i
+		for end+1 < len(values) && values[end+1] == values[end]+1 {
+			end++
+		}
+		switch {
+		case end-i >= 2:
+			parts = append(parts, fmt.Sprintf("%d-%d", values[i], values[end]))
+		case end > i:
+			parts = append(parts, strconv.Itoa(values[i]), strconv.Itoa(values[end]))
+		default:
+			parts = append(parts, strconv.Itoa(values[i]))
+		}
+		i = end + 1
+	}
+	return strings.Join(parts, ",")
+}
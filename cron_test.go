@@ -0,0 +1,58 @@
+package gocron
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJob_ToCron(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	tests := []struct {
+		name     string
+		schedule func() *Scheduler
+		want     string
+	}{
+		{"weekdays", func() *Scheduler {
+			return s.Every(1).Monday().Tuesday().Wednesday().Thursday().Friday().At("09:00")
+		}, "0 9 * * 1-5"},
+		{"daily", func() *Scheduler { return s.Every(1).Day().At("09:30", "18:30") }, "30 9,18 * * *"},
+		{"midnight", func() *Scheduler { return s.Every(1).Day() }, "0 0 * * *"},
+		{"minutes", func() *Scheduler { return s.Every(15).Minutes() }, "*/15 * * * *"},
+		{"seconds as minutes", func() *Scheduler { return s.Every(120).Seconds() }, "*/2 * * * *"},
+		{"hours", func() *Scheduler { return s.Every(6).Hours() }, "0 */6 * * *"},
+		{"hourly", func() *Scheduler { return s.Every(60).Minutes() }, "0 * * * *"},
+		{"days of the month", func() *Scheduler { return s.Every(1).DaysOfMonth(1, 15).At("08:00") }, "0 8 1,15 * *"},
+		{"weekend", func() *Scheduler { return s.Every(1).Sunday().Saturday() }, "0 0 * * 0,6"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := tt.schedule().Do(task)
+			require.NoError(t, err)
+			expr, err := job.ToCron()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, expr)
+		})
+	}
+
+	t.Run("not expressible", func(t *testing.T) {
+		schedules := map[string]func() *Scheduler{
+			"sub-minute":         func() *Scheduler { return s.Every(30).Seconds() },
+			"not dividing":       func() *Scheduler { return s.Every(7).Minutes() },
+			"jitter":             func() *Scheduler { return s.Every(1).Minute().Jitter(time.Second) },
+			"every 2 days":       func() *Scheduler { return s.Every(2).Days() },
+			"weeks":              func() *Scheduler { return s.Every(1).Week() },
+			"seconds of the day": func() *Scheduler { return s.Every(1).Day().At("09:00:30") },
+			"mixed times":        func() *Scheduler { return s.Every(1).Day().At("09:00", "10:30") },
+		}
+		for name, schedule := range schedules {
+			job, err := schedule().Do(task)
+			require.NoError(t, err, name)
+			_, err = job.ToCron()
+			assert.True(t, errors.Is(err, ErrNotCronExpressible), name)
+		}
+	})
+}
@@ -0,0 +1,190 @@
+package gocron
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Logger receives the warnings of the Scheduler. A *log.Logger fits
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// SetLogger sets the Logger warning about the times of the day which a
+// change of the clock makes ambiguous. A nil Logger disables the warnings
+func (s *Scheduler) SetLogger(logger Logger) {
+	s.loggerMutex.Lock()
+	defer s.loggerMutex.Unlock()
+	s.logger = logger
+}
+
+func (s *Scheduler) logf(format string, v ...interface{}) {
+	s.loggerMutex.RLock()
+	logger := s.logger
+	s.loggerMutex.RUnlock()
+	if logger != nil {
+		logger.Printf(format, v...)
+	}
+}
+
+// AmbiguousTimePolicy is when the jobs scheduled at a time of the day run on
+// the days a change of the clock, e.g. for daylight saving time, repeats it
+// or skips it
+type AmbiguousTimePolicy int8
+
+const (
+	// FirstOccurrence runs at the first occurrence of a repeated time, and at
+	// the time a skipped time is moved to, e.g. 03:30 for 02:30 when the clock
+	// goes from 02:00 to 03:00. This is the default
+	FirstOccurrence AmbiguousTimePolicy = iota
+	// SecondOccurrence runs at the second occurrence of a repeated time, and
+	// at the time a skipped time is moved to
+	SecondOccurrence
+	// SkipOccurrence doesn't run on the days a time is repeated or skipped
+	SkipOccurrence
+)
+
+func (p AmbiguousTimePolicy) String() string {
+	switch p {
+	case FirstOccurrence:
+		return "FirstOccurrence"
+	case SecondOccurrence:
+		return "SecondOccurrence"
+	case SkipOccurrence:
+		return "SkipOccurrence"
+	}
+	return fmt.Sprintf("AmbiguousTimePolicy(%d)", p)
+}
+
+// SetAmbiguousTimePolicy sets when the jobs scheduled daily or weekly at a
+// time of the day run on the days the clock repeats or skips that time. Each
+// such day is reported to the Logger set with SetLogger once, when a job is
+// scheduled to run on it, not when its runs are forecast
+func (s *Scheduler) SetAmbiguousTimePolicy(policy AmbiguousTimePolicy) {
+	s.ambiguityMutex.Lock()
+	defer s.ambiguityMutex.Unlock()
+	s.ambiguityPolicy = policy
+}
+
+func (s *Scheduler) getAmbiguousTimePolicy() AmbiguousTimePolicy {
+	s.ambiguityMutex.RLock()
+	defer s.ambiguityMutex.RUnlock()
+	return s.ambiguityPolicy
+}
+
+// resolveAtTime returns the duration from lastRun to the job's next run at
+// atTime, computed by next from a time, applying the AmbiguousTimePolicy on
+// the days the clock repeats or skips atTime
+func (s *Scheduler) resolveAtTime(lastRun time.Time, atTime time.Duration, next func(from time.Time) time.Duration) time.Duration {
+	d := next(lastRun)
+	if d == math.MaxInt64 {
+		return d
+	}
+	nextRun := lastRun.Add(d).In(s.Location())
+	occurrences := wallClockOccurrences(nextRun, atTime)
+	if len(occurrences) == 1 {
+		return s.until(lastRun, occurrences[0])
+	}
+	policy := s.getAmbiguousTimePolicy()
+	switch {
+	case len(occurrences) == 2:
+		switch policy {
+		case FirstOccurrence:
+			nextRun = occurrences[0]
+		case SecondOccurrence:
+			nextRun = occurrences[1]
+		}
+	case len(occurrences) == 0:
+		if policy != SkipOccurrence {
+			nextRun = skippedWallClock(nextRun, atTime)
+		}
+	}
+	if policy == SkipOccurrence || !nextRun.After(lastRun) {
+		// run on the next day the job is scheduled on instead
+		y, m, dd := nextRun.Date()
+		endOfDay := time.Date(y, m, dd+1, 0, 0, 0, 0, s.Location()).Add(-time.Nanosecond)
+		nextRun = endOfDay.Add(next(endOfDay))
+		if occurrences := wallClockOccurrences(nextRun, atTime); len(occurrences) > 0 {
+			nextRun = occurrences[0]
+		}
+	}
+	return s.until(lastRun, nextRun)
+}
+
+// reportAmbiguousTimes reports to the Logger the days of the job's next runs
+// from lastRun on which the clock repeats or skips their time of the day.
+// It's called when scheduling the job, not when forecasting its runs, and
+// reports each such day once
+func (s *Scheduler) reportAmbiguousTimes(job *Job, lastRun time.Time) {
+	s.loggerMutex.RLock()
+	logger := s.logger
+	s.loggerMutex.RUnlock()
+	if logger == nil {
+		return
+	}
+	policy := s.getAmbiguousTimePolicy()
+	s.forEachAtTimeRun(job, func(atTime time.Duration, next func(from time.Time) time.Duration) {
+		d := next(lastRun)
+		if d == math.MaxInt64 {
+			return
+		}
+		nextRun := lastRun.Add(d).In(s.Location())
+		day, clock := nextRun.Format("2006-01-02"), fmt.Sprintf("%02d:%02d", atTime/time.Hour, atTime%time.Hour/time.Minute)
+		var format string
+		switch len(wallClockOccurrences(nextRun, atTime)) {
+		case 2:
+			format = "gocron: job %q: %s occurs twice on %s in %s, applying %s"
+		case 0:
+			format = "gocron: job %q: %s doesn't occur on %s in %s, applying %s"
+		default:
+			return
+		}
+		if job.markAmbiguityReported(day + " " + clock) {
+			s.logf(format, job.Name(), clock, day, s.Location(), policy)
+		}
+	})
+}
+
+// wallClockOccurrences returns the instants the clock shows atTime on the day
+// of t in its location: none if a change of the clock skips it, two if one
+// repeats it
+func wallClockOccurrences(t time.Time, atTime time.Duration) []time.Time {
+	y, m, d := t.Date()
+	wall := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Add(atTime)
+	var occurrences []time.Time
+	for _, offset := range offsetsAround(wall, t.Location()) {
+		instant := wall.Add(-time.Duration(offset) * time.Second).In(t.Location())
+		if sameWallClock(instant, wall) && (len(occurrences) == 0 || !occurrences[0].Equal(instant)) {
+			occurrences = append(occurrences, instant)
+		}
+	}
+	if len(occurrences) == 2 && occurrences[1].Before(occurrences[0]) {
+		occurrences[0], occurrences[1] = occurrences[1], occurrences[0]
+	}
+	return occurrences
+}
+
+// skippedWallClock returns the time atTime, skipped by a change of the clock
+// on the day of t, is moved to by the clock's offset before the change
+func skippedWallClock(t time.Time, atTime time.Duration) time.Time {
+	y, m, d := t.Date()
+	wall := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Add(atTime)
+	offset := offsetsAround(wall, t.Location())[0]
+	return wall.Add(-time.Duration(offset) * time.Second).In(t.Location())
+}
+
+// offsetsAround returns the offsets of the location a day before and after
+// the wall clock time, the same twice if it doesn't change in between
+func offsetsAround(wall time.Time, loc *time.Location) [2]int {
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+	return [2]int{before, after}
+}
+
+func sameWallClock(t, wall time.Time) bool {
+	y, m, d := t.Date()
+	wy, wm, wd := wall.Date()
+	return y == wy && m == wm && d == wd && t.Hour() == wall.Hour() &&
+		t.Minute() == wall.Minute() && t.Second() == wall.Second() && t.Nanosecond() == wall.Nanosecond()
+}
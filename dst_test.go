@@ -0,0 +1,115 @@
+package gocron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestScheduler_SetAmbiguousTimePolicy(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// nextRun returns the next run of a daily job at atTime scheduled after it last ran at lastRun
+	nextRun := func(t *testing.T, policy AmbiguousTimePolicy, atTime string, lastRun time.Time) (time.Time, *recordingLogger) {
+		s := NewScheduler(loc)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return lastRun }}
+		logger := &recordingLogger{}
+		s.SetLogger(logger)
+		s.SetAmbiguousTimePolicy(policy)
+		job, err := s.Every(1).Day().At(atTime).Do(task)
+		require.NoError(t, err)
+		job.setLastRun(lastRun)
+		s.scheduleNextRun(job)
+		return job.NextRun(), logger
+	}
+
+	t.Run("nonexistent time", func(t *testing.T) {
+		// the clock goes from 02:00 to 03:00 on March 14th 2021
+		lastRun := time.Date(2021, time.March, 13, 2, 30, 0, 0, loc)
+		run, logger := nextRun(t, FirstOccurrence, "02:30", lastRun)
+		assert.Equal(t, time.Date(2021, time.March, 14, 3, 30, 0, 0, loc), run)
+		require.Len(t, logger.lines, 1)
+		assert.Contains(t, logger.lines[0], "02:30 doesn't occur on 2021-03-14")
+
+		run, _ = nextRun(t, SkipOccurrence, "02:30", lastRun)
+		assert.Equal(t, time.Date(2021, time.March, 15, 2, 30, 0, 0, loc), run)
+	})
+
+	t.Run("duplicated time", func(t *testing.T) {
+		// the clock goes from 02:00 back to 01:00 on November 7th 2021
+		lastRun := time.Date(2021, time.November, 6, 1, 30, 0, 0, loc)
+		first := time.Date(2021, time.November, 7, 5, 30, 0, 0, time.UTC).In(loc)
+		second := time.Date(2021, time.November, 7, 6, 30, 0, 0, time.UTC).In(loc)
+		next := time.Date(2021, time.November, 8, 1, 30, 0, 0, loc)
+
+		run, logger := nextRun(t, FirstOccurrence, "01:30", lastRun)
+		assert.Equal(t, first, run)
+		require.Len(t, logger.lines, 1)
+		assert.Contains(t, logger.lines[0], "01:30 occurs twice on 2021-11-07")
+		run, _ = nextRun(t, FirstOccurrence, "01:30", first)
+		assert.Equal(t, next, run, "the job shouldn't run again at the second occurrence")
+
+		run, _ = nextRun(t, SecondOccurrence, "01:30", lastRun)
+		assert.Equal(t, second, run)
+		run, _ = nextRun(t, SecondOccurrence, "01:30", second)
+		assert.Equal(t, next, run)
+
+		run, _ = nextRun(t, SkipOccurrence, "01:30", lastRun)
+		assert.Equal(t, next, run)
+	})
+
+	t.Run("weekday", func(t *testing.T) {
+		s := NewScheduler(loc)
+		s.SetAmbiguousTimePolicy(SecondOccurrence)
+		job, err := s.Every(1).Sunday().At("01:30").Do(task)
+		require.NoError(t, err)
+		job.setLastRun(time.Date(2021, time.October, 31, 1, 31, 0, 0, loc))
+		assert.Equal(t, time.Date(2021, time.November, 7, 6, 30, 0, 0, time.UTC), job.LastRun().Add(s.durationToNextRun(job)).UTC())
+	})
+
+	t.Run("reported once when scheduling", func(t *testing.T) {
+		// the clock goes from 02:00 to 03:00 on March 14th 2021
+		lastRun := time.Date(2021, time.March, 13, 2, 30, 0, 0, loc)
+		s := NewScheduler(loc)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return lastRun }}
+		logger := &recordingLogger{}
+		s.SetLogger(logger)
+		job, err := s.Every(1).Day().At("02:30").Do(task)
+		require.NoError(t, err)
+
+		s.NextRunAfter(job, lastRun.AddDate(0, 0, 3))
+		s.RunCountInRange(job, lastRun, lastRun.AddDate(0, 0, 3))
+		assert.Empty(t, logger.lines, "forecasting the runs shouldn't report them")
+
+		job.setLastRun(lastRun)
+		s.scheduleNextRun(job)
+		s.scheduleNextRun(job)
+		require.Len(t, logger.lines, 1)
+		assert.Contains(t, logger.lines[0], "02:30 doesn't occur on 2021-03-14")
+	})
+
+	t.Run("unambiguous day", func(t *testing.T) {
+		run, logger := nextRun(t, SkipOccurrence, "01:30", time.Date(2021, time.June, 1, 1, 30, 0, 0, loc))
+		assert.Equal(t, time.Date(2021, time.June, 2, 1, 30, 0, 0, loc), run)
+		assert.Empty(t, logger.lines)
+	})
+}
+
+func TestAmbiguousTimePolicy_String(t *testing.T) {
+	assert.Equal(t, "FirstOccurrence", FirstOccurrence.String())
+	assert.Equal(t, "SecondOccurrence", SecondOccurrence.String())
+	assert.Equal(t, "SkipOccurrence", SkipOccurrence.String())
+	assert.Equal(t, "AmbiguousTimePolicy(9)", AmbiguousTimePolicy(9).String())
+}
@@ -154,6 +154,20 @@ func ExampleJob_RunCount() {
 	<-s.StartAsync()
 }
 
+func ExampleJob_Timer() {
+	s := gocron.NewScheduler(time.UTC)
+	job, _ := s.Every(1).Second().Do(task)
+	s.StartAsync()
+
+	timer := job.Timer()
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		fmt.Println("the job is running")
+	case <-time.After(2 * time.Second):
+	}
+}
+
 func ExampleJob_RemoveAfterLastRun() {
 	s := gocron.NewScheduler(time.UTC)
 	job, _ := s.Every(1).Second().Do(task)
@@ -0,0 +1,31 @@
+package gocron
+
+import (
+	"expvar"
+	"sync/atomic"
+)
+
+// runStats counts the runs of the jobs of a Scheduler
+type runStats struct {
+	runs    int64 // completed runs
+	errors  int64 // completed runs which returned an error
+	running int64 // runs in progress
+}
+
+// PublishExpvar publishes the metrics of the Scheduler to expvar, as a map
+// named prefix holding the number of jobs, the total number of runs and of
+// runs which returned an error, and the number of jobs currently running:
+//
+//	"gocron": {"jobs": 2, "runs": 10, "errors": 1, "running": 1}
+//
+// Like expvar.Publish, it panics if the name is already published
+func (s *Scheduler) PublishExpvar(prefix string) {
+	expvar.Publish(prefix, expvar.Func(func() interface{} {
+		return map[string]int64{
+			"jobs":    int64(s.Len()),
+			"runs":    atomic.LoadInt64(&s.stats.runs),
+			"errors":  atomic.LoadInt64(&s.stats.errors),
+			"running": atomic.LoadInt64(&s.stats.running),
+		}
+	}))
+}
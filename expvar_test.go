@@ -0,0 +1,44 @@
+package gocron
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expvarRuns makes the expvar names unique across the runs of the tests,
+// e.g. with -count, as they are published once per process
+var expvarRuns int32
+
+func TestScheduler_PublishExpvar(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	name := fmt.Sprintf("%s_%d", t.Name(), atomic.AddInt32(&expvarRuns, 1))
+	s.PublishExpvar(name)
+	stats := func() map[string]int64 {
+		var values map[string]int64
+		require.NoError(t, json.Unmarshal([]byte(expvar.Get(name).String()), &values))
+		return values
+	}
+	assert.Equal(t, map[string]int64{"jobs": 0, "runs": 0, "errors": 0, "running": 0}, stats())
+
+	_, err := s.Every(1).Hour().Do(func() {})
+	require.NoError(t, err)
+	_, err = s.Every(1).Hour().Do(func() error { return errors.New("failed") })
+	require.NoError(t, err)
+	s.RunAll()
+	require.NoError(t, s.WaitForRuns(1, time.Second))
+	s.runningJobs.Wait()
+	assert.Equal(t, map[string]int64{"jobs": 2, "runs": 2, "errors": 1, "running": 0}, stats())
+
+	s.RunAll()
+	require.NoError(t, s.WaitForRuns(2, time.Second))
+	s.runningJobs.Wait()
+	assert.Equal(t, map[string]int64{"jobs": 2, "runs": 4, "errors": 2, "running": 0}, stats())
+}
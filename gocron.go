@@ -11,9 +11,11 @@
 package gocron
 
 import (
+	"context"
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"runtime"
@@ -29,6 +31,27 @@ var (
 	ErrNotScheduledWeekday   = errors.New("job not scheduled weekly on a weekday")
 	ErrJobNotFoundWithTag    = errors.New("no jobs found with given tag")
 	ErrUnsupportedTimeFormat = errors.New("the given time format is not supported")
+	ErrZeroInterval          = errors.New("a zero interval is only allowed for jobs scheduled at a specific time or day")
+	ErrFuncNotRegistered     = errors.New("the job function was not found in the registry")
+	ErrUnknownTimeUnit       = errors.New("unknown time unit")
+	ErrInvalidDayOfMonth     = errors.New("days of the month must be between 1 and 31")
+	ErrParamTypeMismatch     = errors.New("the type of a param does not match the function's signature")
+	ErrInvalidRateLimit      = errors.New("a rate limit needs a positive number of runs and duration")
+	ErrIntervalTooLarge      = errors.New("the interval overflows the maximum duration between runs")
+	ErrWaitTimeout           = errors.New("timed out waiting for the jobs to run")
+	ErrMethodNotFound        = errors.New("the receiver has no exported method with the given name")
+	ErrNoFuncSet             = errors.New("the job has no function set, Do was not called")
+	ErrFuncNotFound          = errors.New("the function of the job is missing from its functions")
+	ErrRunSkipped            = errors.New("the run was skipped")
+	ErrAborted               = errors.New("the run was aborted")
+	ErrRunTimeout            = errors.New("the run timed out")
+	ErrNeverScheduled        = errors.New("the job is never scheduled to run")
+	ErrNoRunTimeParam        = errors.New("the job function takes no time.Time param for the time of the run")
+	ErrInvalidTimesPer       = errors.New("the unit must divide into a whole number of seconds by a positive number of runs")
+	ErrInvalidWeights        = errors.New("weighted functions need at least one entry and positive weights")
+	ErrNotCronExpressible    = errors.New("the schedule can't be expressed as a cron expression")
+	ErrInvalidResourcePool   = errors.New("a resource pool needs a key and a positive number of runs")
+	ErrTriggerCycle          = errors.New("the job would trigger itself")
 )
 
 // regex patterns for supported time formats
@@ -48,18 +71,181 @@ const (
 	months
 )
 
+var timeUnitNames = map[timeUnit]string{
+	seconds: "seconds",
+	minutes: "minutes",
+	hours:   "hours",
+	days:    "days",
+	weeks:   "weeks",
+	months:  "months",
+}
+
+func (u timeUnit) String() string {
+	return timeUnitNames[u]
+}
+
+// unitDurations are the durations of the time units, the longest for the calendar ones
+var unitDurations = map[timeUnit]time.Duration{
+	seconds: time.Second,
+	minutes: time.Minute,
+	hours:   time.Hour,
+	days:    24 * time.Hour,
+	weeks:   7 * 24 * time.Hour,
+	months:  31 * 24 * time.Hour,
+}
+
+// intervalDuration returns the duration of interval units, or
+// ErrIntervalTooLarge if it overflows a time.Duration
+func intervalDuration(interval jobInterval, unit timeUnit) (time.Duration, error) {
+	unitDuration, ok := unitDurations[unit]
+	if !ok {
+		return 0, nil
+	}
+	if uint64(interval) > uint64(math.MaxInt64/unitDuration) {
+		return 0, ErrIntervalTooLarge
+	}
+	return time.Duration(interval) * unitDuration, nil
+}
+
+func parseTimeUnit(name string) (timeUnit, error) {
+	if name == "" {
+		return 0, nil
+	}
+	for unit, unitName := range timeUnitNames {
+		if unitName == name {
+			return unit, nil
+		}
+	}
+	return 0, ErrUnknownTimeUnit
+}
+
+// validateJobFunc checks that jobFunc is a function which can be called with params.
+// The trailing params of a variadic function are checked against its variadic param
+func validateJobFunc(jobFunc interface{}, params []interface{}) error {
+	if jobFunc == nil || reflect.TypeOf(jobFunc).Kind() != reflect.Func {
+		return ErrNotAFunction
+	}
+	params = withRunArgs(context.Background(), time.Time{}, jobFunc, params)
+	typ := reflect.TypeOf(jobFunc)
+	if !isArityAdapted(typ, len(params)) {
+		return ErrParamsNotAdapted
+	}
+	for i, param := range params {
+		if !isAssignable(param, paramType(typ, i)) {
+			return ErrParamTypeMismatch
+		}
+	}
+	return nil
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// withRunArgs prepends the params which jobFunc, a non variadic function,
+// takes before the ones given: ctx if its first param is a context.Context,
+// followed by at if its next param is a time.Time
+func withRunArgs(ctx context.Context, at time.Time, jobFunc interface{}, params []interface{}) []interface{} {
+	typ := reflect.TypeOf(jobFunc)
+	if typ.IsVariadic() {
+		return params
+	}
+	var args []interface{}
+	switch missing := typ.NumIn() - len(params); {
+	case missing == 1 && typ.In(0) == contextType:
+		args = []interface{}{ctx}
+	case missing == 1 && typ.In(0) == timeType:
+		args = []interface{}{at}
+	case missing == 2 && typ.In(0) == contextType && typ.In(1) == timeType:
+		args = []interface{}{ctx, at}
+	default:
+		return params
+	}
+	return append(args, params...)
+}
+
+// takesRunTime returns true if jobFunc, called with params, receives the
+// time of the run from withRunArgs
+func takesRunTime(jobFunc interface{}, params []interface{}) bool {
+	typ := reflect.TypeOf(jobFunc)
+	if typ.IsVariadic() {
+		return false
+	}
+	switch typ.NumIn() - len(params) {
+	case 1:
+		return typ.In(0) == timeType
+	case 2:
+		return typ.In(0) == contextType && typ.In(1) == timeType
+	}
+	return false
+}
+
+// isArityAdapted returns true if a function of type typ can be called with n params
+func isArityAdapted(typ reflect.Type, n int) bool {
+	if typ.IsVariadic() {
+		return n >= typ.NumIn()-1
+	}
+	return n == typ.NumIn()
+}
+
+// paramType returns the type of the i-th param of a function of type typ
+func paramType(typ reflect.Type, i int) reflect.Type {
+	if typ.IsVariadic() && i >= typ.NumIn()-1 {
+		return typ.In(typ.NumIn() - 1).Elem()
+	}
+	return typ.In(i)
+}
+
+// isAssignable returns true if param can be passed as an argument of type typ
+func isAssignable(param interface{}, typ reflect.Type) bool {
+	if param == nil {
+		switch typ.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+			return true
+		default:
+			return false
+		}
+	}
+	return reflect.TypeOf(param).AssignableTo(typ)
+}
+
 func callJobFuncWithParams(jobFunc interface{}, params []interface{}) ([]reflect.Value, error) {
 	f := reflect.ValueOf(jobFunc)
-	if len(params) != f.Type().NumIn() {
+	if !isArityAdapted(f.Type(), len(params)) {
 		return nil, ErrParamsNotAdapted
 	}
 	in := make([]reflect.Value, len(params))
 	for k, param := range params {
+		if param == nil {
+			in[k] = reflect.Zero(paramType(f.Type(), k))
+			continue
+		}
 		in[k] = reflect.ValueOf(param)
 	}
 	return f.Call(in), nil
 }
 
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// callJobFunc calls the job function for the run at the given time and returns
+// the error it returned, if its last result is an error, or the error which
+// prevented calling it
+func callJobFunc(ctx context.Context, at time.Time, jobFunc interface{}, params []interface{}) error {
+	results, err := callJobFuncWithParams(jobFunc, withRunArgs(ctx, at, jobFunc, params))
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return nil
+	}
+	last := results[len(results)-1]
+	if last.Type() != errorType || last.IsNil() {
+		return nil
+	}
+	return last.Interface().(error)
+}
+
 func getFunctionName(fn interface{}) string {
 	return runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
 }
@@ -87,3 +273,15 @@ func parseTime(t string) (hour, min, sec int, err error) {
 	}
 	return parsedTime.Hour(), parsedTime.Minute(), parsedTime.Second(), nil
 }
+
+// ParseAtTime parses a time of day in the form "HH:MM:SS" or "HH:MM", as
+// accepted by At and the windows of a Job, into a duration since midnight.
+// It returns ErrUnsupportedTimeFormat if t is malformed or out of range, e.g.
+// "25:00", allowing to validate a configuration before scheduling
+func ParseAtTime(t string) (time.Duration, error) {
+	hour, min, sec, err := parseTime(t)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute + time.Duration(sec)*time.Second, nil
+}
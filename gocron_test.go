@@ -1,9 +1,12 @@
 package gocron
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseTime(t *testing.T) {
@@ -105,3 +108,116 @@ func TestParseTime(t *testing.T) {
 		})
 	}
 }
+
+func TestParseAtTime(t *testing.T) {
+	valid := map[string]time.Duration{
+		"00:00":    0,
+		"6:08":     6*time.Hour + 8*time.Minute,
+		"16:18":    16*time.Hour + 18*time.Minute,
+		"06:18:01": 6*time.Hour + 18*time.Minute + time.Second,
+		"23:59:59": 23*time.Hour + 59*time.Minute + 59*time.Second,
+	}
+	for s, want := range valid {
+		got, err := ParseAtTime(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+
+	for _, s := range []string{"", "25:00", "24:00", "23:60", "23:59:60", "1e:10", "10", "10:00:00:00", " 10:00"} {
+		_, err := ParseAtTime(s)
+		assert.Equal(t, ErrUnsupportedTimeFormat, err, s)
+	}
+}
+
+func TestCallJobFuncWithParams_Variadic(t *testing.T) {
+	var got []string
+	variadic := func(prefix string, args ...string) {
+		got = append([]string{prefix}, args...)
+	}
+
+	tests := []struct {
+		name    string
+		params  []interface{}
+		want    []string
+		wantErr error
+	}{
+		{
+			name:   "zero trailing args",
+			params: []interface{}{"prefix"},
+			want:   []string{"prefix"},
+		},
+		{
+			name:   "one trailing arg",
+			params: []interface{}{"prefix", "a"},
+			want:   []string{"prefix", "a"},
+		},
+		{
+			name:   "several trailing args",
+			params: []interface{}{"prefix", "a", "b", "c"},
+			want:   []string{"prefix", "a", "b", "c"},
+		},
+		{
+			name:    "missing fixed arg",
+			params:  []interface{}{},
+			wantErr: ErrParamsNotAdapted,
+		},
+		{
+			name:    "wrong trailing arg type",
+			params:  []interface{}{"prefix", "a", 1},
+			wantErr: ErrParamTypeMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			err := validateJobFunc(variadic, tt.params)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.NoError(t, err)
+			_, err = callJobFuncWithParams(variadic, tt.params)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCallJobFunc_Context(t *testing.T) {
+	type key struct{}
+	runCtx := context.WithValue(context.Background(), key{}, "run")
+	givenCtx := context.WithValue(context.Background(), key{}, "given")
+	var got interface{}
+	withContext := func(ctx context.Context, s string) { got = ctx.Value(key{}) }
+
+	require.NoError(t, validateJobFunc(withContext, []interface{}{"a"}))
+	require.NoError(t, callJobFunc(runCtx, time.Time{}, withContext, []interface{}{"a"}))
+	assert.Equal(t, "run", got)
+
+	require.NoError(t, validateJobFunc(withContext, []interface{}{givenCtx, "a"}))
+	require.NoError(t, callJobFunc(runCtx, time.Time{}, withContext, []interface{}{givenCtx, "a"}))
+	assert.Equal(t, "given", got, "a context given in the params is kept")
+
+	assert.Equal(t, ErrParamsNotAdapted, validateJobFunc(withContext, []interface{}{}))
+}
+
+func TestCallJobFunc_RunTime(t *testing.T) {
+	at := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	var got time.Time
+	var gotCtx context.Context
+	withTime := func(runAt time.Time, s string) { got = runAt }
+	withContextAndTime := func(ctx context.Context, runAt time.Time) { gotCtx, got = ctx, runAt }
+
+	require.NoError(t, validateJobFunc(withTime, []interface{}{"a"}))
+	require.NoError(t, callJobFunc(context.Background(), at, withTime, []interface{}{"a"}))
+	assert.Equal(t, at, got)
+
+	given := at.Add(time.Hour)
+	require.NoError(t, callJobFunc(context.Background(), at, withTime, []interface{}{given, "a"}))
+	assert.Equal(t, given, got, "a time given in the params is kept")
+
+	require.NoError(t, validateJobFunc(withContextAndTime, nil))
+	require.NoError(t, callJobFunc(context.Background(), at, withContextAndTime, nil))
+	assert.Equal(t, at, got)
+	assert.NotNil(t, gotCtx)
+}
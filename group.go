@@ -0,0 +1,83 @@
+package gocron
+
+import "sync"
+
+// JobGroup is a named group of Jobs of a Scheduler, e.g. all the billing
+// jobs, which can be paused, resumed, run or removed together
+type JobGroup struct {
+	mu        sync.RWMutex
+	name      string
+	scheduler *Scheduler
+	jobs      []*Job
+}
+
+// NewGroup creates an empty group of Jobs of the Scheduler
+func (s *Scheduler) NewGroup(name string) *JobGroup {
+	return &JobGroup{name: name, scheduler: s}
+}
+
+// Name returns the name of the group
+func (g *JobGroup) Name() string {
+	return g.name
+}
+
+// Add adds Jobs to the group. A Job already in the group isn't added twice
+func (g *JobGroup) Add(jobs ...*Job) *JobGroup {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, job := range jobs {
+		if !g.contains(job) {
+			g.jobs = append(g.jobs, job)
+		}
+	}
+	return g
+}
+
+func (g *JobGroup) contains(job *Job) bool {
+	for _, member := range g.jobs {
+		if member == job {
+			return true
+		}
+	}
+	return false
+}
+
+// Jobs returns the Jobs of the group, in the order they were added
+func (g *JobGroup) Jobs() []*Job {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return append([]*Job(nil), g.jobs...)
+}
+
+// Pause pauses every Job of the group, see Job.Pause
+func (g *JobGroup) Pause() {
+	for _, job := range g.Jobs() {
+		job.Pause()
+	}
+}
+
+// Resume resumes every Job of the group, see Job.Resume
+func (g *JobGroup) Resume() {
+	for _, job := range g.Jobs() {
+		job.Resume()
+	}
+}
+
+// RunAll runs every Job of the group regardless if they are scheduled
+// to run or not, like Scheduler.RunAll
+func (g *JobGroup) RunAll() {
+	for _, job := range g.Jobs() {
+		g.scheduler.run(job)
+	}
+}
+
+// Remove removes every Job of the group from the Scheduler and empties the group
+func (g *JobGroup) Remove() {
+	g.mu.Lock()
+	jobs := g.jobs
+	g.jobs = nil
+	g.mu.Unlock()
+	for _, job := range jobs {
+		g.scheduler.RemoveByReference(job)
+	}
+}
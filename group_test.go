@@ -0,0 +1,52 @@
+package gocron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobGroup(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	newJob := func() *Job {
+		job, err := s.Every(1).Minute().Do(func() {})
+		require.NoError(t, err)
+		job.Sync()
+		s.scheduleNextRun(job)
+		return job
+	}
+	invoice, payment, report := newJob(), newJob(), newJob()
+	billing := s.NewGroup("billing").Add(invoice, payment, invoice)
+	assert.Equal(t, "billing", billing.Name())
+	assert.Equal(t, []*Job{invoice, payment}, billing.Jobs())
+
+	billing.Pause()
+	assert.True(t, invoice.IsPaused())
+	assert.False(t, report.IsPaused())
+	s.RunPending()
+	assert.Equal(t, 0, invoice.RunCount())
+	assert.Equal(t, 1, invoice.SkippedRuns())
+	assert.Equal(t, start.Add(time.Minute), invoice.NextRun(), "a paused job is rescheduled")
+	assert.Equal(t, 1, report.RunCount(), "jobs outside of the group still run")
+
+	billing.Resume()
+	now = start.Add(time.Minute)
+	s.RunPending()
+	assert.Equal(t, 1, invoice.RunCount())
+	assert.Equal(t, 1, payment.RunCount())
+
+	billing.RunAll()
+	assert.Equal(t, 2, invoice.RunCount())
+	assert.Equal(t, 2, payment.RunCount())
+	assert.Equal(t, 2, report.RunCount())
+
+	billing.Remove()
+	assert.Empty(t, billing.Jobs())
+	assert.Equal(t, []*Job{report}, s.Jobs())
+}
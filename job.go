@@ -1,13 +1,26 @@
 package gocron
 
 import (
+	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"golang.org/x/sync/singleflight"
 )
 
+// Reasons for which a trigger of a Job is skipped
+const (
+	// SkipReasonRunning the job is already running with its queue of waiting triggers full
+	SkipReasonRunning = "running"
+	// SkipReasonRateLimited the job exceeded the budget set with RateLimit
+	SkipReasonRateLimited = "rate limited"
+	// SkipReasonPaused the job was paused with Pause
+	SkipReasonPaused = "paused"
+)
+
 // Mode is Job mode
 type Mode int8
 
@@ -17,10 +30,59 @@ const (
 
 	// SingletonMode switch to single job mode
 	SingletonMode
+
+	// OrderedMode run the triggers one at a time, in the order they arrived in
+	OrderedMode
 )
 
+// String returns the name of the Mode, e.g. "SingletonMode"
+func (m Mode) String() string {
+	switch m {
+	case NoMode:
+		return "NoMode"
+	case SingletonMode:
+		return "SingletonMode"
+	case OrderedMode:
+		return "OrderedMode"
+	}
+	return fmt.Sprintf("Mode(%d)", m)
+}
+
 type jobInterval uint64
 
+type intervalsByWeekday map[time.Weekday]jobInterval
+
+// weekParity selects the ISO weeks a Job runs in
+type weekParity int8
+
+const (
+	anyWeek weekParity = iota
+	oddWeeks
+	evenWeeks
+)
+
+// matches returns true if t falls in an ISO week of the parity
+func (p weekParity) matches(t time.Time) bool {
+	if p == anyWeek {
+		return true
+	}
+	_, week := t.ISOWeek()
+	return (week%2 == 1) == (p == oddWeeks)
+}
+
+func (p weekParity) String() string {
+	switch p {
+	case oddWeeks:
+		return "odd"
+	case evenWeeks:
+		return "even"
+	}
+	return ""
+}
+
+// maxRunCount is the highest run count of a Job, at which it stops counting runs
+const maxRunCount = int(^uint(0) >> 1)
+
 // Job struct stores the information necessary to run a Job
 type Job struct {
 	sync.RWMutex
@@ -28,25 +90,141 @@ type Job struct {
 	unit              timeUnit                 // time units, ,e.g. 'minutes', 'hours'...
 	startsImmediately bool                     // if the Job should run upon scheduler start
 	jobFunc           string                   // the Job jobFunc to run, func[jobFunc]
-	atTime            time.Duration            // optional time at which this Job runs
+	atTimes           []time.Duration          // optional times of the day at which this Job runs, sorted
 	err               error                    // error related to Job
 	lastRun           time.Time                // datetime of last run
 	nextRun           time.Time                // datetime of next run
-	scheduledWeekday  *time.Weekday            // Specific day of the week to start on
-	dayOfTheMonth     int                      // Specific day of the month to run the job
+	lastLatency       time.Duration            // delay between the last run's scheduled and actual start
+	scheduledWeekdays []time.Weekday           // Specific days of the week to run on, sorted
+	daysOfTheMonth    []int                    // Specific days of the month to run the job
+	skipsShortMonths  bool                     // if the days beyond the length of a month are skipped, set by DaysOfMonth
+	ambiguityReported string                   // day and time of the day of the last ambiguous run reported
+	lastRunFailed     bool                     // if the last run returned an error
+	jitter            time.Duration            // maximum random delay added to each scheduled run
+	excludedDates     []time.Time              // calendar days on which the job must not run
+	dailyWindow       *timeWindow              // time of the day the job is allowed to run in
+	blackoutWindows   []timeWindow             // times of the day the job must not run in
 	funcs             map[string]interface{}   // Map for the function task store
 	fparams           map[string][]interface{} // Map for function and params of function
+	steps             []jobStep                // functions run after jobFunc, in order
 	tags              []string                 // allow the user to tag Jobs with certain labels
+	labels            map[string]string        // key/value metadata attached to the Job
+	values            jobValues                // values attached to the Job with SetContext
+	name              string                   // optional name identifying the Job
 	runConfig         runConfig                // configuration for how many times to run the job
 	runCount          int                      // number of time the job ran
+	errorCount        int                      // number of runs which returned an error
 	limiter           singleflight.Group       // limits the runs to a single instance
+	singletonTriggers int                      // number of triggers running or waiting in SingletonMode
+	ordered           *orderedQueue            // triggers running or waiting in OrderedMode
+	skippedRuns       int                      // number of triggers that did not run the job
+	coalescedRuns     int                      // number of triggers served by a run in flight or a catch-up run
+	coalesceMissed    bool                     // if the missed runs are caught up with a single run
+	warmUp            bool                     // if a warm-up run is pending
+	scheduleChange    *ScheduleChange          // schedule to switch to after a number of runs
+	errorSchedule     *errorSchedule           // interval to run at after a failed run
+	explanation       nextRunExplanation       // how the next run was computed
+	weekdayIntervals  intervalsByWeekday       // intervals replacing the interval on some weekdays
+	startDelay        time.Duration            // minimum delay between scheduling the job and its first run
+	phase             *time.Duration           // offset of the runs from the times the interval divides the day into
+	resourcePool      *resourcePool            // pool limiting the runs of the jobs sharing it
+	deadline          time.Time                // time after which the job is removed, set with LimitDurationTo
+	weekParity        weekParity               // parity of the ISO weeks the job runs in
+	runCtx            context.Context          // context of the current runs, cancelled by AbortCurrentRun
+	cancelRun         context.CancelFunc       // cancels runCtx
+	timeout           time.Duration            // duration after which the context of a run is cancelled
+	activeRuns        int                      // runs in progress, including the ones reserved by TryRunNow
+	runningCalls      int                      // runs calling the job's functions
+	overlapCount      int                      // number of runs started while another was in progress
+	lastSuccessfulRun time.Time                // start of the last run which returned no error
+	paused            bool                     // if the scheduled runs are skipped
+	runWaiters        []chan RunResult         // receive the result of the next run, see WaitForNextRun
+	onSkip            func(reason string)      // called whenever a trigger is skipped
+	onComplete        func()                   // called once the last run allowed by LimitRunsTo completed
+	triggers          []*Job                   // jobs run by the scheduler after each successful run
+	completed         bool                     // if the last run allowed by LimitRunsTo started
+	rateLimit         *tokenBucket             // limits the number of runs over time
+	onTagChange       func(old, new []string)  // called whenever the tags are modified
+	nextRunFunc       scheduleFunc             // computes the next run in place of the interval
+	onReschedule      scheduleFunc             // adjusts the computed next run on each reschedule
+}
+
+// scheduleFunc computes the next run of a Job from its last run
+type scheduleFunc func(last time.Time) time.Time
+
+// timeWindow is a range of time of the day, as durations since
+// midnight. A window ending before its start spans midnight
+type timeWindow struct {
+	start time.Duration
+	end   time.Duration
+}
+
+func newTimeWindow(start, end string) (*timeWindow, error) {
+	startTime, err := ParseAtTime(start)
+	if err != nil {
+		return nil, ErrTimeFormat
+	}
+	endTime, err := ParseAtTime(end)
+	if err != nil {
+		return nil, ErrTimeFormat
+	}
+	return &timeWindow{start: startTime, end: endTime}, nil
+}
+
+// contains returns true if the time of the day of t is within the window, bounds included
+func (w timeWindow) contains(t time.Time) bool {
+	timeOfDay := timeOfDay(t)
+	if w.start <= w.end {
+		return timeOfDay >= w.start && timeOfDay <= w.end
+	}
+	return timeOfDay >= w.start || timeOfDay <= w.end
+}
+
+// blackedOut returns true if the time of the day of t is within the window, end excluded
+func (w timeWindow) blackedOut(t time.Time) bool {
+	timeOfDay := timeOfDay(t)
+	if w.start <= w.end {
+		return timeOfDay >= w.start && timeOfDay < w.end
+	}
+	return timeOfDay >= w.start || timeOfDay < w.end
+}
+
+// timeOfDay returns the duration elapsed since midnight on the clock of t
+func timeOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
+
+// jobStep is a function chained to the Job's function with Then
+type jobStep struct {
+	jobFunc interface{}
+	params  []interface{}
 }
 
 type runConfig struct {
 	finiteRuns         bool
 	maxRuns            int
+	maxDuration        time.Duration
 	mode               Mode
 	removeAfterLastRun bool
+	sync               bool
+	continueOnError    bool
+	finiteQueue        bool
+	maxQueue           int
+	critical           bool
+}
+
+// SingletonOption configures the behavior of a Job in SingletonMode or OrderedMode
+type SingletonOption func(*runConfig)
+
+// WithMaxQueue limits to n the number of triggers waiting for the
+// in-flight run of a Job in SingletonMode or OrderedMode. Additional
+// triggers are dropped and counted in SkippedRuns
+func WithMaxQueue(n int) SingletonOption {
+	return func(rc *runConfig) {
+		rc.finiteQueue = true
+		rc.maxQueue = n
+	}
 }
 
 // NewJob creates a new Job with the provided interval
@@ -58,54 +236,1138 @@ func NewJob(interval uint64) *Job {
 		funcs:             make(map[string]interface{}),
 		fparams:           make(map[string][]interface{}),
 		tags:              []string{},
+		labels:            make(map[string]string),
 		startsImmediately: true,
 	}
 }
 
-// Run the Job and immediately reschedule it
-func (j *Job) run() {
+// nextRunExplanation records how the next run of a Job was computed
+type nextRunExplanation struct {
+	source          string        // what computed the next run, before jitter and constraints
+	jitter          time.Duration // random delay added to the next run
+	adjustedFrom    time.Time     // the next run before OnReschedule changed it, if it did
+	constrainedFrom time.Time     // the next run before the constraints moved it, if they did
+}
+
+func (j *Job) setNextRunExplanation(explanation nextRunExplanation) {
+	j.Lock()
+	defer j.Unlock()
+	j.explanation = explanation
+}
+
+// ExplainNextRun describes how the next run of the Job was computed, e.g.
+// "nextRun=2020-01-06T09:00:12Z, every 1 weeks, weekdays=Monday, atTimes=09:00, jitter=+12s"
+func (j *Job) ExplainNextRun() string {
+	j.RLock()
+	defer j.RUnlock()
+	if j.nextRun.IsZero() {
+		return "not scheduled"
+	}
+	parts := []string{"nextRun=" + j.nextRun.Format(time.RFC3339)}
+	source := j.explanation.source
+	if source == "" {
+		source = "set with StartAt"
+	}
+	parts = append(parts, source)
+	if j.jitter > 0 {
+		parts = append(parts, "jitter=+"+j.explanation.jitter.String())
+	}
+	if !j.explanation.adjustedFrom.IsZero() {
+		parts = append(parts, "adjusted from "+j.explanation.adjustedFrom.Format(time.RFC3339)+" by OnReschedule")
+	}
+	if !j.explanation.constrainedFrom.IsZero() {
+		parts = append(parts, "moved from "+j.explanation.constrainedFrom.Format(time.RFC3339)+" by the windows or excluded dates")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeSchedule describes the interval of the Job and when it's anchored
+func (j *Job) describeSchedule() string {
+	j.RLock()
+	defer j.RUnlock()
+	parts := []string{fmt.Sprintf("every %d %s", j.interval, j.unit)}
+	if len(j.scheduledWeekdays) > 0 {
+		weekdays := make([]string, 0, len(j.scheduledWeekdays))
+		for _, weekday := range j.scheduledWeekdays {
+			weekdays = append(weekdays, weekday.String())
+		}
+		parts = append(parts, "weekdays="+strings.Join(weekdays, ","))
+	}
+	if len(j.daysOfTheMonth) > 0 && j.daysOfTheMonth[0] > 0 {
+		days := make([]string, 0, len(j.daysOfTheMonth))
+		for _, day := range j.daysOfTheMonth {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "daysOfMonth="+strings.Join(days, ","))
+	}
+	if len(j.atTimes) > 0 {
+		atTimes := make([]string, 0, len(j.atTimes))
+		for _, atTime := range j.atTimes {
+			atTimes = append(atTimes, fmt.Sprintf("%02d:%02d", atTime/time.Hour, (atTime%time.Hour)/time.Minute))
+		}
+		parts = append(parts, "atTimes="+strings.Join(atTimes, ","))
+	}
+	if j.weekParity != anyWeek {
+		parts = append(parts, "isoWeeks="+j.weekParity.String())
+	}
+	if j.phase != nil {
+		parts = append(parts, "phase="+j.phase.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ScheduleChange is a change of the schedule of a Job once it ran a number
+// of times, set with AfterRuns and ChangeTo
+type ScheduleChange struct {
+	job      *Job
+	runs     int
+	interval jobInterval
+	unit     timeUnit
+}
+
+// AfterRuns starts the change of the Job's schedule once it ran n times,
+// completed with ChangeTo, e.g. AfterRuns(5).ChangeTo(5, "minutes")
+func (j *Job) AfterRuns(n int) *ScheduleChange {
+	return &ScheduleChange{job: j, runs: n}
+}
+
+// ChangeTo sets the interval the Job runs at after the number of runs given
+// to AfterRuns, in the unit named "seconds", "minutes", "hours", "days",
+// "weeks" or "months". The change applies when the Job is rescheduled, which
+// for asynchronous jobs may be before their last run is counted
+func (c *ScheduleChange) ChangeTo(interval uint64, unit string) *Job {
+	j := c.job
+	parsedInterval, timeUnit, err := parseInterval(interval, unit)
+	j.Lock()
+	defer j.Unlock()
+	if err != nil {
+		j.err = err
+		return j
+	}
+	c.interval, c.unit = parsedInterval, timeUnit
+	j.scheduleChange = c
+	return j
+}
+
+// parseInterval validates an interval in the unit with the given name
+func parseInterval(interval uint64, unit string) (jobInterval, timeUnit, error) {
+	timeUnit, err := parseTimeUnit(unit)
+	if err == nil && timeUnit == 0 {
+		err = ErrUnknownTimeUnit
+	}
+	if err == nil && interval == 0 {
+		err = ErrZeroInterval
+	}
+	if err == nil {
+		_, err = intervalDuration(jobInterval(interval), timeUnit)
+	}
+	return jobInterval(interval), timeUnit, err
+}
+
+// TimesPer sets the interval of the Job to run n times per unit, named like
+// in ChangeTo, e.g. TimesPer(4, "hours") runs every 15 minutes. The interval
+// must be a whole number of seconds, and months, which vary in length, can't
+// be divided. The change applies when the Job is rescheduled
+func (j *Job) TimesPer(n int, unit string) *Job {
+	interval, timeUnit, err := timesPer(n, unit)
+	j.Lock()
+	defer j.Unlock()
+	if err != nil {
+		j.err = err
+		return j
+	}
+	j.interval, j.unit = interval, timeUnit
+	return j
+}
+
+// timesPer divides one unit with the given name by n, returning the interval
+// in the largest unit it's a whole number of
+func timesPer(n int, unit string) (jobInterval, timeUnit, error) {
+	parsedUnit, err := parseTimeUnit(unit)
+	if err == nil && parsedUnit == 0 {
+		err = ErrUnknownTimeUnit
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	unitDuration := unitDurations[parsedUnit]
+	if n <= 0 || parsedUnit == months || unitDuration%time.Duration(n) != 0 {
+		return 0, 0, ErrInvalidTimesPer
+	}
+	d := unitDuration / time.Duration(n)
+	for _, u := range []timeUnit{hours, minutes, seconds} {
+		if d%unitDurations[u] == 0 {
+			return jobInterval(d / unitDurations[u]), u, nil
+		}
+	}
+	return 0, 0, ErrInvalidTimesPer
+}
+
+// errorSchedule is the interval a Job runs at after a failed run, set
+// with OnErrorReschedule
+type errorSchedule struct {
+	interval jobInterval
+	unit     timeUnit
+}
+
+// OnErrorReschedule schedules the next run of the Job after a failed run at
+// the given interval, instead of its own, in the unit named like in ChangeTo,
+// e.g. OnErrorReschedule(1, "minutes"). Its own interval is restored after a
+// successful run. The next runs of the Job are scheduled once the run
+// completed, as its error is known only then
+func (j *Job) OnErrorReschedule(interval uint64, unit string) *Job {
+	parsedInterval, timeUnit, err := parseInterval(interval, unit)
+	j.Lock()
+	defer j.Unlock()
+	if err != nil {
+		j.err = err
+		return j
+	}
+	j.errorSchedule = &errorSchedule{interval: parsedInterval, unit: timeUnit}
+	return j
+}
+
+// errorInterval returns the interval until the next run of the Job if its
+// last run failed and it was set with OnErrorReschedule
+func (j *Job) errorInterval() (time.Duration, bool) {
+	j.RLock()
+	defer j.RUnlock()
+	// configuration errors, which the error of the Job also holds, don't count
+	if j.errorSchedule == nil || !j.lastRunFailed {
+		return 0, false
+	}
+	d, err := intervalDuration(j.errorSchedule.interval, j.errorSchedule.unit)
+	return d, err == nil
+}
+
+func (j *Job) setLastRunFailed(failed bool) {
+	j.Lock()
+	defer j.Unlock()
+	j.lastRunFailed = failed
+}
+
+func (j *Job) getLastRunFailed() bool {
+	j.RLock()
+	defer j.RUnlock()
+	return j.lastRunFailed
+}
+
+func (j *Job) hasErrorSchedule() bool {
+	j.RLock()
+	defer j.RUnlock()
+	return j.errorSchedule != nil
+}
+
+// applyScheduleChange switches to the pending schedule once the Job ran enough times
+func (j *Job) applyScheduleChange() {
+	j.Lock()
+	defer j.Unlock()
+	if j.scheduleChange == nil || j.runCount < j.scheduleChange.runs {
+		return
+	}
+	j.interval, j.unit = j.scheduleChange.interval, j.scheduleChange.unit
+	j.scheduleChange = nil
+}
+
+// defaultWaitPoll is the interval at which WaitUntil checks its condition when given none
+const defaultWaitPoll = 100 * time.Millisecond
+
+// WaitUntil blocks the Job's function calling it until cond returns true,
+// checking it every poll, and returns nil. It returns the context's error
+// as soon as ctx is done, e.g. when shutting down
+func (j *Job) WaitUntil(ctx context.Context, cond func() bool, poll time.Duration) error {
+	if poll <= 0 {
+		poll = defaultWaitPoll
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for !cond() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// WarmUp runs the Job's functions once as soon as the Job is scheduled, e.g.
+// to prime caches. The warm-up run doesn't count as a run of the Job: it
+// doesn't count towards LimitRunsTo nor delay the Job's first scheduled run
+func (j *Job) WarmUp() {
+	j.Lock()
+	defer j.Unlock()
+	j.warmUp = true
+}
+
+// takeWarmUp returns true once if a warm-up run is pending
+func (j *Job) takeWarmUp() bool {
+	j.Lock()
+	defer j.Unlock()
+	warmUp := j.warmUp
+	j.warmUp = false
+	return warmUp
+}
+
+// Clone returns an independent copy of the Job with the same schedule,
+// functions, params and configuration, but which never ran
+func (j *Job) Clone() *Job {
+	j.RLock()
+	defer j.RUnlock()
+	clone := NewJob(uint64(j.interval))
+	clone.unit = j.unit
+	clone.startsImmediately = j.startsImmediately
+	clone.jobFunc = j.jobFunc
+	clone.name = j.name
+	clone.atTimes = append([]time.Duration(nil), j.atTimes...)
+	clone.scheduledWeekdays = append([]time.Weekday(nil), j.scheduledWeekdays...)
+	clone.daysOfTheMonth = append([]int(nil), j.daysOfTheMonth...)
+	clone.skipsShortMonths = j.skipsShortMonths
+	clone.jitter = j.jitter
+	clone.excludedDates = append([]time.Time(nil), j.excludedDates...)
+	if j.dailyWindow != nil {
+		window := *j.dailyWindow
+		clone.dailyWindow = &window
+	}
+	clone.blackoutWindows = append([]timeWindow(nil), j.blackoutWindows...)
+	clone.startDelay = j.startDelay
+	clone.weekParity = j.weekParity
+	clone.timeout = j.timeout
+	if j.weekdayIntervals != nil {
+		clone.weekdayIntervals = make(intervalsByWeekday, len(j.weekdayIntervals))
+		for weekday, interval := range j.weekdayIntervals {
+			clone.weekdayIntervals[weekday] = interval
+		}
+	}
+	for name, f := range j.funcs {
+		clone.funcs[name] = f
+	}
+	for name, params := range j.fparams {
+		clone.fparams[name] = append([]interface{}(nil), params...)
+	}
+	for _, step := range j.steps {
+		step.params = append([]interface{}(nil), step.params...)
+		clone.steps = append(clone.steps, step)
+	}
+	clone.tags = append(clone.tags, j.tags...)
+	for key, value := range j.labels {
+		clone.labels[key] = value
+	}
+	for key, value := range j.values {
+		clone.SetContext(key, value)
+	}
+	clone.runConfig = j.runConfig
+	clone.onSkip = j.onSkip
+	clone.onComplete = j.onComplete
+	clone.onTagChange = j.onTagChange
+	clone.nextRunFunc = j.nextRunFunc
+	clone.onReschedule = j.onReschedule
+	clone.coalesceMissed = j.coalesceMissed
+	clone.errorSchedule = j.errorSchedule
+	clone.resourcePool = j.resourcePool
+	if j.phase != nil {
+		phase := *j.phase
+		clone.phase = &phase
+	}
+	if j.rateLimit != nil {
+		clone.rateLimit = j.rateLimit.reset()
+	}
+	if j.scheduleChange != nil {
+		change := *j.scheduleChange
+		change.job = clone
+		clone.scheduleChange = &change
+	}
+	return clone
+}
+
+// Run the Job and immediately reschedule it. It returns the error of the run,
+// or ErrRunSkipped if the trigger was skipped
+func (j *Job) run() error {
+	return j.runWith(runOptions{})
+}
+
+// runOptions are the settings of the Scheduler applying to the runs of a Job
+type runOptions struct {
+	defaultTimeout time.Duration    // timeout of the Job unless it has its own
+	tracer         Tracer           // starts a span for each run, if set
+	overrun        OverrunPolicy    // applies to the Job in NoMode
+	now            func() time.Time // the clock of the Scheduler, time.Now if nil
+	ticket         *orderedTicket   // place of the trigger in OrderedMode, taken when it arrived
+}
+
+// time returns the current time on the clock of the Scheduler, if set
+func (o runOptions) time() time.Time {
+	if o.now == nil {
+		return time.Now()
+	}
+	return o.now()
+}
+
+// runWith runs the Job like run, with the settings of the Scheduler
+func (j *Job) runWith(opts runOptions) error {
+	j.RLock()
+	mode := runMode(j.runConfig.mode, opts.overrun)
+	rateLimit := j.rateLimit
+	j.RUnlock()
+	// the bucket refills on the clock of the Scheduler
+	if rateLimit != nil && !rateLimit.take(opts.time()) {
+		j.skip(SkipReasonRateLimited)
+		return ErrRunSkipped
+	}
+	var err error
+	switch mode {
+	case SingletonMode:
+		if !j.joinSingletonQueue() {
+			j.skip(SkipReasonRunning)
+			return ErrRunSkipped
+		}
+		defer j.leaveSingletonQueue()
+		var executed, shared bool
+		_, err, shared = j.limiter.Do("main", func() (interface{}, error) {
+			executed = true
+			return nil, j.call(opts)
+		})
+		// the trigger which executed the call also reports it as shared
+		if shared && !executed {
+			j.coalesce()
+		}
+	case OrderedMode:
+		ticket := opts.ticket
+		if ticket == nil {
+			ticket = j.takeOrderedTicket(opts.overrun)
+		}
+		if !ticket.wait() {
+			j.skip(SkipReasonRunning)
+			return ErrRunSkipped
+		}
+		defer ticket.release()
+		err = j.call(opts)
+	default:
+		err = j.call(opts)
+	}
+	j.setErr(err)
+	return err
+}
+
+// RunNowAsync runs the Job immediately in its own goroutine, outside of its
+// schedule, and returns a channel delivering the result of the run once it
+// completes. In SingletonMode, a trigger sharing the in-flight run receives
+// its result. A skipped trigger delivers ErrRunSkipped
+func (j *Job) RunNowAsync() <-chan RunResult {
+	result := make(chan RunResult, 1)
+	go func() {
+		start := time.Now()
+		err := j.run()
+		result <- RunResult{Job: j, Start: start, Duration: time.Since(start), Err: err}
+		close(result)
+	}()
+	return result
+}
+
+// call counts a run of the Job and invokes its functions
+func (j *Job) call(opts runOptions) error {
+	// a job without a function doesn't run, so its runs aren't counted
+	if err := j.funcErr(); err != nil {
+		return err
+	}
+	j.Lock()
+	// saturate rather than wrap to a negative count, which would let a
+	// job limited with LimitRunsTo run again
+	if j.runCount < maxRunCount {
+		j.runCount++
+	}
+	j.activeRuns++
+	if j.runningCalls > 0 {
+		j.overlapCount++
+	}
+	j.runningCalls++
+	// the run reaching the limit of runs completes the job
+	var onComplete func()
+	if j.runConfig.finiteRuns && j.runCount >= j.runConfig.maxRuns && !j.completed {
+		j.completed = true
+		onComplete = j.onComplete
+	}
+	j.Unlock()
+	defer j.endCall()
+	start := opts.time()
+	err := j.callFuncs(opts)
+	j.setLastRunFailed(err != nil)
+	if err == nil {
+		j.setLastSuccessfulRun(start)
+	} else {
+		j.countError()
+	}
+	j.notifyRunWaiters(RunResult{Job: j, Start: start, Duration: opts.time().Sub(start), Err: err})
+	if onComplete != nil {
+		onComplete()
+	}
+	return err
+}
+
+// WaitForNextRun blocks until the next run of the Job completes and returns
+// its result, or returns ErrWaitTimeout once timeout elapsed. A run in
+// progress when it's called counts as the next run
+func (j *Job) WaitForNextRun(timeout time.Duration) (RunResult, error) {
+	// subscribe before waiting so that a run completing in between isn't missed
+	waiter := make(chan RunResult, 1)
+	j.Lock()
+	j.runWaiters = append(j.runWaiters, waiter)
+	j.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case result := <-waiter:
+		return result, nil
+	case <-timer.C:
+		j.removeRunWaiter(waiter)
+		return RunResult{}, ErrWaitTimeout
+	}
+}
+
+func (j *Job) removeRunWaiter(waiter chan RunResult) {
+	j.Lock()
+	defer j.Unlock()
+	for i, w := range j.runWaiters {
+		if w == waiter {
+			j.runWaiters = append(j.runWaiters[:i], j.runWaiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// notifyRunWaiters sends the result of a run to the goroutines waiting for it
+func (j *Job) notifyRunWaiters(result RunResult) {
+	j.Lock()
+	waiters := j.runWaiters
+	j.runWaiters = nil
+	j.Unlock()
+	for _, waiter := range waiters {
+		waiter <- result
+	}
+}
+
+// LastSuccessfulRun returns the time the last run of the Job which returned
+// no error started at. Unlike LastRun, it doesn't change on failed runs
+func (j *Job) LastSuccessfulRun() time.Time {
+	j.RLock()
+	defer j.RUnlock()
+	return j.lastSuccessfulRun
+}
+
+func (j *Job) setLastSuccessfulRun(t time.Time) {
+	j.Lock()
+	defer j.Unlock()
+	j.lastSuccessfulRun = t
+}
+
+func (j *Job) endRun() {
+	j.Lock()
+	defer j.Unlock()
+	j.activeRuns--
+}
+
+// endCall ends a run started by call
+func (j *Job) endCall() {
+	j.Lock()
+	defer j.Unlock()
+	j.runningCalls--
+	j.activeRuns--
+}
+
+// OverlapCount returns the number of runs of the Job which started while a
+// previous run was still in progress, which a Job in NoMode allows. A high
+// count suggests running the Job in SingletonMode
+func (j *Job) OverlapCount() int {
+	j.RLock()
+	defer j.RUnlock()
+	return j.overlapCount
+}
+
+// isRunning returns true if a run of the Job is in progress
+func (j *Job) isRunning() bool {
+	j.RLock()
+	defer j.RUnlock()
+	return j.activeRuns > 0
+}
+
+// TryRunNow runs the Job immediately in its own goroutine, like RunNowAsync,
+// unless a run is already in progress or the rate limit of the Job is
+// exhausted. It then returns false without running nor queuing the trigger,
+// e.g. to tell the user that the Job is busy
+func (j *Job) TryRunNow() bool {
+	j.Lock()
+	// like the run it starts, which is outside of the Scheduler, on the wall clock
+	if j.activeRuns > 0 || (j.rateLimit != nil && !j.rateLimit.available(time.Now())) {
+		j.Unlock()
+		return false
+	}
+	j.activeRuns++ // reserve the run until it starts
+	j.Unlock()
+	go func() {
+		defer j.endRun()
+		j.run()
+	}()
+	return true
+}
+
+// callFuncs invokes the Job's functions without holding the lock so that
+// the Job can still be inspected and rescheduled while it's running
+func (j *Job) callFuncs(opts runOptions) error {
+	j.RLock()
+	if j.funcs[j.jobFunc] == nil {
+		j.RUnlock()
+		return j.funcErr()
+	}
+	steps := append([]jobStep{{jobFunc: j.funcs[j.jobFunc], params: j.fparams[j.jobFunc]}}, j.steps...)
+	continueOnError := j.runConfig.continueOnError
+	timeout := j.timeout
+	spanName := j.name
+	if spanName == "" {
+		spanName = j.jobFunc
+	}
+	j.RUnlock()
+	if timeout <= 0 {
+		timeout = opts.defaultTimeout
+	}
+
+	ctx, at := j.runContext(), opts.time()
+	if opts.tracer != nil {
+		var span Span
+		ctx, span = opts.tracer.Start(ctx, spanName)
+		defer span.End()
+		err := callSteps(ctx, at, timeout, steps, continueOnError)
+		if err != nil {
+			span.RecordError(err)
+		}
+		return err
+	}
+	return callSteps(ctx, at, timeout, steps, continueOnError)
+}
+
+// callSteps invokes the functions of the run at the given time with its context
+func callSteps(ctx context.Context, at time.Time, timeout time.Duration, steps []jobStep, continueOnError bool) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var firstErr error
+	for _, step := range steps {
+		err := callJobFunc(ctx, at, step.jobFunc, step.params)
+		if ctx.Err() == context.DeadlineExceeded {
+			return ErrRunTimeout
+		}
+		if ctx.Err() != nil {
+			return ErrAborted
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			if !continueOnError {
+				break
+			}
+		}
+	}
+	return firstErr
+}
+
+// runContext returns the context of the Job's runs, created on first use
+func (j *Job) runContext() context.Context {
+	j.Lock()
+	defer j.Unlock()
+	if j.runCtx == nil {
+		j.runCtx, j.cancelRun = context.WithCancel(context.Background())
+	}
+	return j.runCtx
+}
+
+// SetTimeout cancels the context of each run of the Job after d, the run
+// then recording ErrRunTimeout. Like with AbortCurrentRun, functions
+// ignoring the context aren't stopped. It overrides the default timeout
+// of the Scheduler, a timeout of 0 or less restoring it
+func (j *Job) SetTimeout(d time.Duration) {
+	j.Lock()
+	defer j.Unlock()
+	j.timeout = d
+}
+
+// AbortCurrentRun cancels the context of the runs of the Job in progress,
+// which then record ErrAborted, e.g. to test the handling of failures.
+// The functions of the Job receive the context of their run if their first
+// param is a context.Context not given in the params of Do. Functions
+// ignoring the context aren't stopped, although their runs still record
+// ErrAborted. The later runs get a new context
+func (j *Job) AbortCurrentRun() {
+	j.Lock()
+	defer j.Unlock()
+	if j.cancelRun != nil {
+		j.cancelRun()
+	}
+	j.runCtx, j.cancelRun = nil, nil
+}
+
+// joinSingletonQueue registers a trigger in SingletonMode. It returns
+// false if the queue of triggers waiting for the in-flight run is full
+func (j *Job) joinSingletonQueue() bool {
+	j.Lock()
+	defer j.Unlock()
+	// the first trigger runs the job, the following ones wait for it
+	if j.runConfig.finiteQueue && j.singletonTriggers > j.runConfig.maxQueue {
+		return false
+	}
+	j.singletonTriggers++
+	return true
+}
+
+func (j *Job) leaveSingletonQueue() {
+	j.Lock()
+	defer j.Unlock()
+	j.singletonTriggers--
+}
+
+// skip counts a trigger which did not run the job and notifies the OnSkip callback
+func (j *Job) skip(reason string) {
+	j.Lock()
+	j.skippedRuns++
+	onSkip := j.onSkip
+	j.Unlock()
+	if onSkip != nil {
+		onSkip(reason)
+	}
+}
+
+// Pause skips the scheduled runs of the Job until Resume is called. The runs
+// due while paused are counted in SkippedRuns and the Job is rescheduled
+func (j *Job) Pause() {
+	j.Lock()
+	defer j.Unlock()
+	j.paused = true
+}
+
+// Resume lets the scheduled runs of a paused Job run again
+func (j *Job) Resume() {
+	j.Lock()
+	defer j.Unlock()
+	j.paused = false
+}
+
+// IsPaused returns true if the Job was paused with Pause
+func (j *Job) IsPaused() bool {
+	j.RLock()
+	defer j.RUnlock()
+	return j.paused
+}
+
+// OnComplete sets a callback invoked once the last run of a Job limited
+// with LimitRunsTo completed, whatever its result
+func (j *Job) OnComplete(f func()) {
+	j.Lock()
+	defer j.Unlock()
+	j.onComplete = f
+}
+
+// TriggerOnCompletionOf makes the scheduler run the Job each time a completes
+// a run successfully, in addition to the Job's own schedule, which is left
+// untouched. Only the runs started by the scheduler trigger the Job, and
+// a paused Job skips the trigger. Triggering a Job from a twice has no
+// effect, while a trigger which would make the Job trigger itself, directly
+// or through other jobs, sets ErrTriggerCycle
+func (j *Job) TriggerOnCompletionOf(a *Job) *Job {
+	triggersMutex.Lock()
+	defer triggersMutex.Unlock()
+	if a == j || j.triggersJob(a) {
+		j.setErr(ErrTriggerCycle)
+		return j
+	}
+	a.Lock()
+	defer a.Unlock()
+	for _, triggered := range a.triggers {
+		if triggered == j {
+			return j
+		}
+	}
+	a.triggers = append(a.triggers, j)
+	return j
+}
+
+// triggersMutex makes checking for a cycle and adding a trigger one step,
+// not to let concurrent triggers form a cycle across jobs
+var triggersMutex sync.Mutex
+
+// triggersJob returns true if the runs of the Job trigger target, directly
+// or through other jobs
+func (j *Job) triggersJob(target *Job) bool {
+	visited := map[*Job]bool{j: true}
+	pending := j.getTriggers()
+	for len(pending) > 0 {
+		job := pending[0]
+		pending = pending[1:]
+		if job == target {
+			return true
+		}
+		if !visited[job] {
+			visited[job] = true
+			pending = append(pending, job.getTriggers()...)
+		}
+	}
+	return false
+}
+
+func (j *Job) getTriggers() []*Job {
+	j.RLock()
+	defer j.RUnlock()
+	return append([]*Job(nil), j.triggers...)
+}
+
+// OnSkip sets a callback invoked with the reason whenever
+// a trigger of the Job is skipped
+func (j *Job) OnSkip(f func(reason string)) {
+	j.Lock()
+	defer j.Unlock()
+	j.onSkip = f
+}
+
+// hasFunc returns true if the function of the Job was set with Do
+func (j *Job) hasFunc() bool {
+	j.RLock()
+	defer j.RUnlock()
+	return j.funcs[j.jobFunc] != nil
+}
+
+// funcErr returns ErrNoFuncSet if Do was not called, or ErrFuncNotFound if
+// the function the Job refers to is missing from its functions
+func (j *Job) funcErr() error {
+	j.RLock()
+	defer j.RUnlock()
+	switch {
+	case j.funcs[j.jobFunc] != nil:
+		return nil
+	case j.jobFunc == "":
+		return ErrNoFuncSet
+	}
+	return ErrFuncNotFound
+}
+
+func (j *Job) setErr(err error) {
+	j.Lock()
+	defer j.Unlock()
+	j.err = err
+}
+
+func (j *Job) neverRan() bool {
+	j.RLock()
+	defer j.RUnlock()
+	return j.lastRun.IsZero()
+}
+
+func (j *Job) getStartsImmediately() bool {
+	j.RLock()
+	defer j.RUnlock()
+	return j.startsImmediately
+}
+
+func (j *Job) setStartsImmediately(b bool) {
+	j.Lock()
+	defer j.Unlock()
+	j.startsImmediately = b
+}
+
+// getAtTime returns the earliest time of the day the Job runs at
+func (j *Job) getAtTime() time.Duration {
+	j.RLock()
+	defer j.RUnlock()
+	if len(j.atTimes) == 0 {
+		return 0
+	}
+	return j.atTimes[0]
+}
+
+func (j *Job) getAtTimes() []time.Duration {
+	j.RLock()
+	defer j.RUnlock()
+	return j.atTimes
+}
+
+func (j *Job) setAtTimes(times []time.Duration) {
+	j.Lock()
+	defer j.Unlock()
+	j.atTimes = times
+}
+
+func (j *Job) getScheduledWeekdays() []time.Weekday {
+	j.RLock()
+	defer j.RUnlock()
+	return j.scheduledWeekdays
+}
+
+// isAnchored returns true if the Job is scheduled at a specific time or day
+// rather than purely by its interval
+func (j *Job) isAnchored() bool {
+	j.RLock()
+	defer j.RUnlock()
+	return len(j.atTimes) > 0 || len(j.scheduledWeekdays) > 0 || (len(j.daysOfTheMonth) > 0 && j.daysOfTheMonth[0] > 0)
+}
+
+func (j *Job) getJitter() time.Duration {
+	j.RLock()
+	defer j.RUnlock()
+	return j.jitter
+}
+
+func (j *Job) setJitter(d time.Duration) {
+	j.Lock()
+	defer j.Unlock()
+	j.jitter = d
+}
+
+// Between restricts the runs of the Job to the daily window from start to end,
+// in the form "HH:MM:SS" or "HH:MM". A run falling after the end of the window
+// is moved to the start of the window the following day. A window ending
+// before it starts, e.g. Between("22:00", "06:00"), spans midnight
+func (j *Job) Between(start, end string) *Job {
+	window, err := newTimeWindow(start, end)
+	j.Lock()
+	defer j.Unlock()
+	if err != nil {
+		j.err = err
+		return j
+	}
+	j.dailyWindow = window
+	return j
+}
+
+// Phase runs the Job at the given offset from the times its interval
+// divides the day into, counted from midnight, e.g. at 20 past each hour
+// for an hourly Job with an offset of 20 minutes. Unlike Jitter, the runs
+// are at fixed times, which spreads the load of Jobs sharing an interval
+// reproducibly. It applies to the Jobs running at an interval of seconds,
+// minutes or hours, whose first run is then at the first such time
+func (j *Job) Phase(offset time.Duration) *Job {
+	j.Lock()
+	defer j.Unlock()
+	j.phase = &offset
+	j.startsImmediately = false
+	return j
+}
+
+func (j *Job) getPhase() (time.Duration, bool) {
+	j.RLock()
+	defer j.RUnlock()
+	if j.phase == nil {
+		return 0, false
+	}
+	return *j.phase, true
+}
+
+// StartDelay delays the first run of the Job, whether it starts immediately
+// or at a scheduled time, to at least d after it's scheduled, e.g. when the
+// scheduler starts. A scheduled first run falling within the delay is moved
+// to the end of the delay
+func (j *Job) StartDelay(d time.Duration) *Job {
+	j.Lock()
+	defer j.Unlock()
+	j.startDelay = d
+	return j
+}
+
+func (j *Job) getStartDelay() time.Duration {
+	j.RLock()
+	defer j.RUnlock()
+	if j.startDelay < 0 {
+		return 0
+	}
+	return j.startDelay
+}
+
+// IntervalByWeekday sets the intervals of the Job on some days of the week,
+// e.g. every 5 minutes on weekdays and every 30 minutes on weekends. The
+// interval of a run is the one of the day of the previous run, or the Job's
+// interval for the days missing from intervals. It applies to Jobs scheduled
+// in seconds, minutes or hours
+func (j *Job) IntervalByWeekday(intervals map[time.Weekday]uint64) *Job {
+	weekdayIntervals := make(intervalsByWeekday, len(intervals))
+	var err error
+	for weekday, interval := range intervals {
+		if interval == 0 {
+			err = ErrZeroInterval
+		}
+		weekdayIntervals[weekday] = jobInterval(interval)
+	}
+	j.Lock()
+	defer j.Unlock()
+	for _, interval := range weekdayIntervals {
+		if _, intervalErr := intervalDuration(interval, j.unit); intervalErr != nil {
+			err = intervalErr
+		}
+	}
+	if err != nil {
+		j.err = err
+		return j
+	}
+	j.weekdayIntervals = weekdayIntervals
+	return j
+}
+
+// intervalAt returns the interval of the Job for a run following one at lastRun
+func (j *Job) intervalAt(lastRun time.Time) jobInterval {
+	j.RLock()
+	defer j.RUnlock()
+	if interval, ok := j.weekdayIntervals[lastRun.Weekday()]; ok {
+		return interval
+	}
+	return j.interval
+}
+
+// RateLimit limits the Job to bursts of n runs, refilled at a rate of n runs
+// per the given duration, whichever way the runs are triggered. Triggers
+// exceeding the budget are skipped
+func (j *Job) RateLimit(n int, per time.Duration) *Job {
+	j.Lock()
+	defer j.Unlock()
+	if n <= 0 || per <= 0 {
+		j.err = ErrInvalidRateLimit
+		return j
+	}
+	j.rateLimit = newTokenBucket(n, per)
+	return j
+}
+
+// NextRunFunc sets a function computing the next run of the Job from its last
+// run, in place of the Scheduler's Strategy. A next run not after the last
+// run is ignored and the next run is computed by the Strategy instead, so
+// that the Job can't run in a busy loop
+func (j *Job) NextRunFunc(f func(last time.Time) time.Time) {
+	j.Lock()
+	defer j.Unlock()
+	j.nextRunFunc = f
+}
+
+// OnReschedule sets f to adjust the next run of the Job each time it's
+// computed, e.g. to snap it to a boundary. f is called with the next run
+// computed by the scheduler, jitter included, and returns the next run to
+// use instead, or the zero time to keep it. The windows and excluded dates
+// still apply to the adjusted next run
+func (j *Job) OnReschedule(f func(current time.Time) time.Time) {
+	j.Lock()
+	defer j.Unlock()
+	j.onReschedule = f
+}
+
+func (j *Job) getOnReschedule() scheduleFunc {
+	j.RLock()
+	defer j.RUnlock()
+	return j.onReschedule
+}
+
+func (j *Job) getNextRunFunc() scheduleFunc {
+	j.RLock()
+	defer j.RUnlock()
+	return j.nextRunFunc
+}
+
+// BlackoutWindow prevents the Job from running in the daily window from start
+// to end, in the form "HH:MM:SS" or "HH:MM". A run falling in the window is
+// deferred to its end. It can be called multiple times to add several windows
+func (j *Job) BlackoutWindow(start, end string) *Job {
+	window, err := newTimeWindow(start, end)
+	j.Lock()
+	defer j.Unlock()
+	if err != nil {
+		j.err = err
+		return j
+	}
+	j.blackoutWindows = append(j.blackoutWindows, *window)
+	return j
+}
+
+// ISOWeekParity restricts the Job to the odd or even ISO weeks, as numbered
+// by time.Time.ISOWeek, e.g. every Monday of the odd weeks. As years have
+// 52 or 53 ISO weeks, a 53rd week and the following first week are both odd.
+// See Scheduler.SetWeekStart for weeks starting on another day than Monday
+func (j *Job) ISOWeekParity(odd bool) *Job {
 	j.Lock()
 	defer j.Unlock()
-	switch j.runConfig.mode {
-	case SingletonMode:
-		_, j.err, _ = j.limiter.Do("main", func() (interface{}, error) {
-			j.runCount++
-			return callJobFuncWithParams(j.funcs[j.jobFunc], j.fparams[j.jobFunc])
-		})
-	default:
-		j.runCount++
-		_, j.err = callJobFuncWithParams(j.funcs[j.jobFunc], j.fparams[j.jobFunc])
+	j.weekParity = evenWeeks
+	if odd {
+		j.weekParity = oddWeeks
 	}
+	return j
 }
 
-func (j *Job) neverRan() bool {
+func (j *Job) getWeekParity() weekParity {
 	j.RLock()
 	defer j.RUnlock()
-	return j.lastRun.IsZero()
+	return j.weekParity
 }
 
-func (j *Job) getStartsImmediately() bool {
+func (j *Job) getBlackoutWindows() []timeWindow {
 	j.RLock()
 	defer j.RUnlock()
-	return j.startsImmediately
+	return j.blackoutWindows
 }
 
-func (j *Job) setStartsImmediately(b bool) {
+func (j *Job) getDailyWindow() *timeWindow {
+	j.RLock()
+	defer j.RUnlock()
+	return j.dailyWindow
+}
+
+// ExcludeDates prevents the Job from running on the calendar days of the given
+// dates. A run falling on one of them is moved to the next valid occurrence
+func (j *Job) ExcludeDates(dates ...time.Time) {
 	j.Lock()
 	defer j.Unlock()
-	j.startsImmediately = b
+	for _, date := range dates {
+		j.excludedDates = append(j.excludedDates, calendarDay(date))
+	}
 }
 
-func (j *Job) getAtTime() time.Duration {
+func (j *Job) isExcludedDate(t time.Time) bool {
+	j.RLock()
+	defer j.RUnlock()
+	day := calendarDay(t)
+	for _, excludedDate := range j.excludedDates {
+		if excludedDate.Equal(day) {
+			return true
+		}
+	}
+	return false
+}
+
+func (j *Job) excludedDatesCount() int {
 	j.RLock()
 	defer j.RUnlock()
-	return j.atTime
+	return len(j.excludedDates)
+}
+
+// calendarDay normalizes t to the midnight UTC of its date in its own location
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Then chains a function to run after the Job's function, and after any
+// function previously chained, within the same run. A run stops at the first
+// function returning an error, unless ContinueOnError is set
+func (j *Job) Then(jobFun interface{}, params ...interface{}) *Job {
+	j.Lock()
+	defer j.Unlock()
+	if err := validateJobFunc(jobFun, params); err != nil {
+		j.err = err
+		return j
+	}
+	j.steps = append(j.steps, jobStep{jobFunc: jobFun, params: params})
+	return j
 }
 
-func (j *Job) setAtTime(t time.Duration) {
+// ContinueOnError runs all the functions chained with Then even if
+// one of them returns an error. The run reports the first error
+func (j *Job) ContinueOnError() *Job {
 	j.Lock()
 	defer j.Unlock()
-	j.atTime = t
+	j.runConfig.continueOnError = true
+	return j
 }
 
 // Err returns an error if one occurred while creating the Job
@@ -115,36 +1377,176 @@ func (j *Job) Err() error {
 	return j.err
 }
 
+// LastError returns the error of the last run of the Job, or of its
+// creation, like Err
+func (j *Job) LastError() error {
+	return j.Err()
+}
+
 // Tag allows you to add arbitrary labels to a Job that do not
 // impact the functionality of the Job
 func (j *Job) Tag(t string, others ...string) {
-	j.Lock()
-	defer j.Unlock()
-	j.tags = append(j.tags, t)
-	for _, tag := range others {
-		j.tags = append(j.tags, tag)
-	}
+	j.updateTags(func(tags []string) []string {
+		tags = append(tags, t)
+		return append(tags, others...)
+	})
 }
 
 // Untag removes a tag from a Job
 func (j *Job) Untag(t string) {
+	j.updateTags(func(tags []string) []string {
+		var newTags []string
+		for _, tag := range tags {
+			if t != tag {
+				newTags = append(newTags, tag)
+			}
+		}
+		return newTags
+	})
+}
+
+// OnTagChange sets a function called with copies of the old and new tags
+// whenever the tags of the Job are modified
+func (j *Job) OnTagChange(f func(old, new []string)) {
 	j.Lock()
 	defer j.Unlock()
-	var newTags []string
-	for _, tag := range j.tags {
-		if t != tag {
-			newTags = append(newTags, tag)
-		}
+	j.onTagChange = f
+}
+
+// updateTags replaces the tags with the result of update, calling the
+// OnTagChange function outside of the lock if they changed
+func (j *Job) updateTags(update func(tags []string) []string) {
+	j.Lock()
+	old := copyTags(j.tags)
+	j.tags = update(j.tags)
+	changed := !equalTags(old, j.tags)
+	updated := copyTags(j.tags)
+	onTagChange := j.onTagChange
+	j.Unlock()
+	if changed && onTagChange != nil {
+		onTagChange(old, updated)
 	}
+}
+
+func copyTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(tags)), tags...)
+}
 
-	j.tags = newTags
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
-// Tags returns the tags attached to the Job
+// Tags returns a copy of the tags attached to the Job
 func (j *Job) Tags() []string {
 	j.RLock()
 	defer j.RUnlock()
-	return j.tags
+	return copyTags(j.tags)
+}
+
+// SetName names the Job, e.g. to find it later with Scheduler.FindJobByName
+func (j *Job) SetName(name string) {
+	j.Lock()
+	defer j.Unlock()
+	j.name = name
+}
+
+// Name returns the name of the Job, empty if it isn't named
+func (j *Job) Name() string {
+	j.RLock()
+	defer j.RUnlock()
+	return j.name
+}
+
+// FuncName returns the name of the Job's function, e.g. "main.backup",
+// as matched by Scheduler.FindJobsByFunc
+func (j *Job) FuncName() string {
+	j.RLock()
+	defer j.RUnlock()
+	return j.jobFunc
+}
+
+// SetLabel attaches a key/value label to the Job, replacing
+// any previous value for the key
+func (j *Job) SetLabel(key, value string) {
+	j.Lock()
+	defer j.Unlock()
+	j.labels[key] = value
+}
+
+// Labels returns a copy of the labels attached to the Job
+func (j *Job) Labels() map[string]string {
+	j.RLock()
+	defer j.RUnlock()
+	labels := make(map[string]string, len(j.labels))
+	for key, value := range j.labels {
+		labels[key] = value
+	}
+	return labels
+}
+
+// jobValues are the values attached to a Job, by key
+type jobValues map[interface{}]interface{}
+
+// SetContext attaches value to the Job under key, replacing any previous
+// value for the key, e.g. the configuration the Job was created from. Like
+// for context.WithValue, key should be of a type defined by the caller to
+// avoid collisions, and must be comparable
+func (j *Job) SetContext(key, value interface{}) {
+	j.Lock()
+	defer j.Unlock()
+	if j.values == nil {
+		j.values = make(jobValues)
+	}
+	j.values[key] = value
+}
+
+// Value returns the value attached to the Job under key with SetContext,
+// or nil if there is none
+func (j *Job) Value(key interface{}) interface{} {
+	j.RLock()
+	defer j.RUnlock()
+	return j.values[key]
+}
+
+func (j *Job) hasLabel(key, value string) bool {
+	j.RLock()
+	defer j.RUnlock()
+	v, ok := j.labels[key]
+	return ok && v == value
+}
+
+// Params returns a copy of the parameters the Job's function was registered with
+func (j *Job) Params() []interface{} {
+	j.RLock()
+	defer j.RUnlock()
+	params := make([]interface{}, len(j.fparams[j.jobFunc]))
+	copy(params, j.fparams[j.jobFunc])
+	return params
+}
+
+// Interval returns the number of units between the runs of the Job
+func (j *Job) Interval() uint64 {
+	j.RLock()
+	defer j.RUnlock()
+	return uint64(j.interval)
+}
+
+// Unit returns the name of the time unit of the Job's interval, e.g. "minutes"
+func (j *Job) Unit() string {
+	j.RLock()
+	defer j.RUnlock()
+	return j.unit.String()
 }
 
 // ScheduledTime returns the time of the Job's next scheduled run
@@ -154,22 +1556,52 @@ func (j *Job) ScheduledTime() time.Time {
 	return j.nextRun
 }
 
-// ScheduledAtTime returns the specific time of day the Job will run at
+// ScheduledAtTime returns the specific time of day the Job will run at,
+// the earliest one if it runs at several
 func (j *Job) ScheduledAtTime() string {
+	return formatAtTime(j.getAtTime())
+}
+
+// ScheduledAtTimes returns the specific times of day the Job will run at
+func (j *Job) ScheduledAtTimes() []string {
 	j.RLock()
 	defer j.RUnlock()
-	return fmt.Sprintf("%d:%d", j.atTime/time.Hour, (j.atTime%time.Hour)/time.Minute)
+	times := make([]string, 0, len(j.atTimes))
+	for _, atTime := range j.atTimes {
+		times = append(times, formatAtTime(atTime))
+	}
+	return times
+}
+
+func formatAtTime(atTime time.Duration) string {
+	return fmt.Sprintf("%d:%d", atTime/time.Hour, (atTime%time.Hour)/time.Minute)
 }
 
-// Weekday returns which day of the week the Job will run on and
-// will return an error if the Job is not scheduled weekly
+// Weekday returns which day of the week the Job will run on, the first
+// one if it runs on several, and will return an error if the Job is not
+// scheduled weekly
 func (j *Job) Weekday() (time.Weekday, error) {
 	j.RLock()
 	defer j.RUnlock()
-	if j.scheduledWeekday == nil {
+	if len(j.scheduledWeekdays) == 0 {
 		return time.Sunday, ErrNotScheduledWeekday
 	}
-	return *j.scheduledWeekday, nil
+	return j.scheduledWeekdays[0], nil
+}
+
+// Weekdays returns the days of the week the Job will run on
+func (j *Job) Weekdays() []time.Weekday {
+	j.RLock()
+	defer j.RUnlock()
+	return append([]time.Weekday(nil), j.scheduledWeekdays...)
+}
+
+// ScheduledDaysOfMonth returns the days of the month the Job runs on,
+// if it is scheduled monthly
+func (j *Job) ScheduledDaysOfMonth() []int {
+	j.RLock()
+	defer j.RUnlock()
+	return append([]int{}, j.daysOfTheMonth...)
 }
 
 // LimitRunsTo limits the number of executions of this
@@ -182,11 +1614,192 @@ func (j *Job) LimitRunsTo(n int) {
 	j.runConfig.maxRuns = n
 }
 
+// LimitDurationTo limits the lifetime of the job to d from its first
+// scheduling, e.g. to run every minute for the next hour. Once the
+// deadline has passed, the job is removed from the scheduler. Combined
+// with LimitRunsTo, the job stops at whichever limit comes first
+func (j *Job) LimitDurationTo(d time.Duration) {
+	j.Lock()
+	defer j.Unlock()
+	j.runConfig.maxDuration = d
+}
+
+// startLifetime records the deadline of the job from its first scheduling
+func (j *Job) startLifetime(now time.Time) {
+	j.Lock()
+	defer j.Unlock()
+	if j.runConfig.maxDuration > 0 && j.deadline.IsZero() {
+		j.deadline = now.Add(j.runConfig.maxDuration)
+	}
+}
+
+// expired returns true if the lifetime of the job has passed at now
+func (j *Job) expired(now time.Time) bool {
+	j.RLock()
+	defer j.RUnlock()
+	return !j.deadline.IsZero() && !now.Before(j.deadline)
+}
+
 // SingletonMode Sets the mode to block startup if the current job has not finished
-func (j *Job) SingletonMode() {
+func (j *Job) SingletonMode(opts ...SingletonOption) {
 	j.Lock()
 	defer j.Unlock()
 	j.runConfig.mode = SingletonMode
+	for _, opt := range opts {
+		opt(&j.runConfig)
+	}
+}
+
+// Ordered sets the Job in OrderedMode: unlike SingletonMode, where the
+// triggers share the in-flight run, each trigger waits for the previous
+// ones and runs in turn, in the order they arrived in. Up to 100 triggers
+// wait by default, which can be changed with WithMaxQueue
+func (j *Job) Ordered(opts ...SingletonOption) {
+	j.Lock()
+	defer j.Unlock()
+	j.runConfig.mode = OrderedMode
+	for _, opt := range opts {
+		opt(&j.runConfig)
+	}
+}
+
+// getOrderedQueue returns the queue of the triggers in OrderedMode, created
+// on first use, and the number of triggers which can wait in it
+func (j *Job) getOrderedQueue() (*orderedQueue, int) {
+	j.Lock()
+	defer j.Unlock()
+	if j.ordered == nil {
+		j.ordered = newOrderedQueue()
+	}
+	if j.runConfig.finiteQueue {
+		return j.ordered, j.runConfig.maxQueue
+	}
+	return j.ordered, defaultOrderedQueue
+}
+
+// runMode returns the mode the triggers of a Job in mode run in: the overrun
+// policy queues or skips the triggers of a job in NoMode in the queue of
+// OrderedMode, where no trigger waits when skipping
+func runMode(mode Mode, overrun OverrunPolicy) Mode {
+	if mode == NoMode && (overrun == QueueOverrun || overrun == SkipOverrun) {
+		return OrderedMode
+	}
+	return mode
+}
+
+// takeOrderedTicket gives an arriving trigger its place in the queue of
+// OrderedMode. It returns nil if the triggers of the Job don't run in it
+func (j *Job) takeOrderedTicket(overrun OverrunPolicy) *orderedTicket {
+	j.RLock()
+	mode := j.runConfig.mode
+	j.RUnlock()
+	if runMode(mode, overrun) != OrderedMode {
+		return nil
+	}
+	queue, capacity := j.getOrderedQueue()
+	if mode == NoMode && overrun == SkipOverrun {
+		capacity = 0
+	}
+	return queue.take(capacity)
+}
+
+// markAmbiguityReported records the report of the ambiguous time of a run,
+// identified by its day and time of the day, and returns false if it was
+// already the last one reported
+func (j *Job) markAmbiguityReported(key string) bool {
+	j.Lock()
+	defer j.Unlock()
+	if j.ambiguityReported == key {
+		return false
+	}
+	j.ambiguityReported = key
+	return true
+}
+
+// SetMode sets the mode of the Job, e.g. back to NoMode to let its runs
+// overlap again. It takes effect from the next trigger
+func (j *Job) SetMode(mode Mode) {
+	j.Lock()
+	defer j.Unlock()
+	j.runConfig.mode = mode
+}
+
+// Async runs each trigger of the Job in its own goroutine so that slow
+// jobs don't block the scheduler or other jobs. This is the default.
+// Use SingletonMode to prevent the runs from overlapping.
+func (j *Job) Async() {
+	j.Lock()
+	defer j.Unlock()
+	j.runConfig.sync = false
+}
+
+// Sync runs each trigger of the Job within the scheduler loop, preserving
+// the order of the runs at the cost of blocking the scheduler until
+// the Job returns
+func (j *Job) Sync() {
+	j.Lock()
+	defer j.Unlock()
+	j.runConfig.sync = true
+}
+
+func (j *Job) isSync() bool {
+	j.RLock()
+	defer j.RUnlock()
+	return j.runConfig.sync
+}
+
+// CoalesceMissed makes the Job catch up with the runs it missed, e.g. while
+// paused or while the scheduler was stopped, with a single run, after which
+// it's rescheduled as usual. The missed runs beyond the first are counted in
+// CoalescedRuns. Otherwise, the runs missed while paused are skipped and the
+// ones missed while the scheduler was stopped are dropped
+func (j *Job) CoalesceMissed() *Job {
+	j.Lock()
+	defer j.Unlock()
+	j.coalesceMissed = true
+	return j
+}
+
+func (j *Job) coalescesMissed() bool {
+	j.RLock()
+	defer j.RUnlock()
+	return j.coalesceMissed
+}
+
+// Critical marks the Job as critical: the scheduler stops as soon as
+// one of its runs returns an error
+func (j *Job) Critical() *Job {
+	j.Lock()
+	defer j.Unlock()
+	j.runConfig.critical = true
+	return j
+}
+
+func (j *Job) isCritical() bool {
+	j.RLock()
+	defer j.RUnlock()
+	return j.runConfig.critical
+}
+
+// MaxRuns returns the number of runs the Job is limited to with LimitRunsTo
+func (j *Job) MaxRuns() int {
+	j.RLock()
+	defer j.RUnlock()
+	return j.runConfig.maxRuns
+}
+
+// IsFiniteRuns returns true if the number of runs of the Job is limited
+func (j *Job) IsFiniteRuns() bool {
+	j.RLock()
+	defer j.RUnlock()
+	return j.runConfig.finiteRuns
+}
+
+// Mode returns the mode the Job runs in
+func (j *Job) Mode() Mode {
+	j.RLock()
+	defer j.RUnlock()
+	return j.runConfig.mode
 }
 
 // shouldRun evaluates if this job should run again
@@ -217,43 +1830,127 @@ func (j *Job) NextRun() time.Time {
 	return j.nextRun
 }
 
+// Timer returns a timer firing at the time of the job's next run. It is a
+// one-shot preview that is not tied to the scheduler: it neither runs the job
+// nor follows its rescheduling. A job that has not been scheduled yet fires
+// the timer immediately
+func (j *Job) Timer() *time.Timer {
+	return time.NewTimer(time.Until(j.NextRun()))
+}
+
 func (j *Job) setNextRun(t time.Time) {
 	j.Lock()
 	defer j.Unlock()
 	j.nextRun = t
 }
 
-// RunCount returns the number of time the job ran so far
+// LastLatency returns how late the job's last run started
+// compared to the time it was scheduled at
+func (j *Job) LastLatency() time.Duration {
+	j.RLock()
+	defer j.RUnlock()
+	return j.lastLatency
+}
+
+func (j *Job) setLastLatency(d time.Duration) {
+	j.Lock()
+	defer j.Unlock()
+	j.lastLatency = d
+}
+
+// RunCount returns the number of time the job ran so far. It stops
+// increasing once it reaches the highest int
 func (j *Job) RunCount() int {
 	j.RLock()
 	defer j.RUnlock()
 	return j.runCount
 }
 
-func (j *Job) setRunCount(i int) {
+// ErrorCount returns the number of runs of the Job which returned an
+// error, timed out or were aborted. The skipped triggers aren't counted
+func (j *Job) ErrorCount() int {
+	j.RLock()
+	defer j.RUnlock()
+	return j.errorCount
+}
+
+func (j *Job) countError() {
 	j.Lock()
 	defer j.Unlock()
-	j.runCount = i
+	if j.errorCount < maxRunCount {
+		j.errorCount++
+	}
 }
 
-// RemoveAfterLastRun update the job in order to remove the job after its last exec
-func (j *Job) RemoveAfterLastRun() *Job {
+// ResetRunCount resets the number of runs of the Job and of the runs which
+// returned an error, letting a Job limited with LimitRunsTo run and complete
+// again
+func (j *Job) ResetRunCount() {
 	j.Lock()
 	defer j.Unlock()
-	j.runConfig.removeAfterLastRun = true
-	return j
+	j.runCount = 0
+	j.errorCount = 0
+	j.completed = false
+}
+
+// Reset clears the runs of the Job, their counts and its error, keeping its
+// schedule and functions, so that it's scheduled again as if it was just
+// added, without catching up with the runs it missed. Use Scheduler.ResetJob
+// to schedule its next run while the scheduler is running
+func (j *Job) Reset() {
+	j.Lock()
+	defer j.Unlock()
+	j.lastRun = time.Time{}
+	j.nextRun = time.Time{}
+	j.lastSuccessfulRun = time.Time{}
+	j.lastLatency = 0
+	j.runCount = 0
+	j.errorCount = 0
+	j.completed = false
+	j.err = nil
+	j.lastRunFailed = false
+}
+
+func (j *Job) coalesce() {
+	j.addCoalescedRuns(1)
+}
+
+func (j *Job) addCoalescedRuns(n int) {
+	j.Lock()
+	defer j.Unlock()
+	j.coalescedRuns += n
 }
 
-func (j *Job) getFiniteRuns() bool {
+// CoalescedRuns returns the number of times the job was triggered in
+// SingletonMode while a run was in flight, and shared its result instead
+// of starting a new run, plus the missed runs caught up by another one
+// with CoalesceMissed
+func (j *Job) CoalescedRuns() int {
 	j.RLock()
 	defer j.RUnlock()
-	return j.runConfig.finiteRuns
+	return j.coalescedRuns
 }
 
-func (j *Job) getMaxRuns() int {
+// SkippedRuns returns the number of times the job was triggered
+// but did not run
+func (j *Job) SkippedRuns() int {
 	j.RLock()
 	defer j.RUnlock()
-	return j.runConfig.maxRuns
+	return j.skippedRuns
+}
+
+func (j *Job) setRunCount(i int) {
+	j.Lock()
+	defer j.Unlock()
+	j.runCount = i
+}
+
+// RemoveAfterLastRun update the job in order to remove the job after its last exec
+func (j *Job) RemoveAfterLastRun() *Job {
+	j.Lock()
+	defer j.Unlock()
+	j.runConfig.removeAfterLastRun = true
+	return j
 }
 
 func (j *Job) getRemoveAfterLastRun() bool {
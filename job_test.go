@@ -1,12 +1,17 @@
 package gocron
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTags(t *testing.T) {
@@ -22,6 +27,103 @@ func TestTags(t *testing.T) {
 	assert.ElementsMatch(t, j.Tags(), []string{"tags", "tag", "some"})
 }
 
+func TestTags_Concurrent(t *testing.T) {
+	j, _ := NewScheduler(time.UTC).Every(1).Minute().Do(task)
+	j.Tag("initial")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			j.Tag(fmt.Sprintf("tag%d", i))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			tags := j.Tags()
+			for k := range tags {
+				tags[k] = "modified"
+			}
+		}
+	}()
+	wg.Wait()
+
+	tags := j.Tags()
+	assert.Len(t, tags, 101)
+	assert.Equal(t, "initial", tags[0], "modifying the returned tags must not modify the job's")
+}
+
+func TestJob_OnTagChange(t *testing.T) {
+	j, _ := NewScheduler(time.UTC).Every(1).Minute().Do(task)
+	j.Tag("some")
+
+	type change struct{ old, new []string }
+	var changes []change
+	j.OnTagChange(func(old, new []string) {
+		// the lock must be released when the callback runs
+		assert.Equal(t, new, j.Tags())
+		changes = append(changes, change{old, new})
+		new[0] = "modified"
+	})
+
+	j.Tag("tag", "more")
+	j.Untag("missing")
+	j.Untag("some")
+
+	assert.Equal(t, []change{
+		{old: []string{"some"}, new: []string{"modified", "tag", "more"}},
+		{old: []string{"some", "tag", "more"}, new: []string{"modified", "more"}},
+	}, changes)
+	assert.Equal(t, []string{"tag", "more"}, j.Tags())
+}
+
+func TestLabels(t *testing.T) {
+	j, _ := NewScheduler(time.UTC).Every(1).Minute().Do(task)
+	j.SetLabel("env", "dev")
+	j.SetLabel("team", "billing")
+	j.SetLabel("env", "prod")
+	assert.Equal(t, map[string]string{"env": "prod", "team": "billing"}, j.Labels())
+
+	labels := j.Labels()
+	labels["env"] = "staging"
+	assert.Equal(t, "prod", j.Labels()["env"], "mutating the returned labels should not affect the job")
+}
+
+func TestJob_SetContext(t *testing.T) {
+	type configKey struct{}
+	type config struct {
+		Endpoint string
+		Retries  int
+	}
+	j, _ := NewScheduler(time.UTC).Every(1).Minute().Do(task)
+	assert.Nil(t, j.Value(configKey{}))
+
+	j.SetContext(configKey{}, config{Endpoint: "https://example.com", Retries: 3})
+	j.SetContext("other", 1)
+	cfg, ok := j.Value(configKey{}).(config)
+	require.True(t, ok)
+	assert.Equal(t, config{Endpoint: "https://example.com", Retries: 3}, cfg)
+	assert.Equal(t, 1, j.Value("other"))
+
+	j.SetContext("other", 2)
+	assert.Equal(t, 2, j.Value("other"), "a value should replace the previous one for the key")
+	assert.Equal(t, cfg, j.Clone().Value(configKey{}))
+}
+
+func TestParams(t *testing.T) {
+	j, _ := NewScheduler(time.UTC).Every(1).Minute().Do(taskWithParams, 1, "hello")
+	assert.Equal(t, []interface{}{1, "hello"}, j.Params())
+
+	params := j.Params()
+	params[0] = 2
+	assert.Equal(t, []interface{}{1, "hello"}, j.Params(), "mutating the returned params should not affect the job")
+
+	j, _ = NewScheduler(time.UTC).Every(1).Minute().Do(task)
+	assert.Empty(t, j.Params())
+}
+
 func TestSingletonMode(t *testing.T) {
 	s := NewScheduler(time.UTC)
 	var trigger int32
@@ -39,6 +141,591 @@ func TestSingletonMode(t *testing.T) {
 	s.Stop()
 }
 
+func TestSingletonMode_WithMaxQueue(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	job, _ := s.Every(1).Second().Do(func() {
+		time.Sleep(500 * time.Millisecond)
+	})
+	job.SingletonMode(WithMaxQueue(1))
+	var skipMutex sync.Mutex
+	var skipReasons []string
+	job.OnSkip(func(reason string) {
+		skipMutex.Lock()
+		defer skipMutex.Unlock()
+		skipReasons = append(skipReasons, reason)
+	})
+
+	var wg sync.WaitGroup
+	trigger := func() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			job.run()
+		}()
+	}
+	trigger()
+	time.Sleep(50 * time.Millisecond)
+	for i := 0; i < 4; i++ {
+		trigger()
+		time.Sleep(10 * time.Millisecond)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, job.RunCount(), "waiting triggers should be served by the in-flight run")
+	assert.Equal(t, 3, job.SkippedRuns(), "triggers beyond the queue limit should be skipped")
+	assert.Equal(t, []string{SkipReasonRunning, SkipReasonRunning, SkipReasonRunning}, skipReasons)
+}
+
+func TestJob_CoalescedRuns(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	job, err := s.Every(1).Second().Do(func() {
+		time.Sleep(200 * time.Millisecond)
+	})
+	require.NoError(t, err)
+	job.SingletonMode()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			job.run()
+		}()
+		time.Sleep(10 * time.Millisecond)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, job.RunCount())
+	assert.Equal(t, 4, job.CoalescedRuns(), "the triggers during the run should share it")
+	assert.Equal(t, 0, job.SkippedRuns())
+}
+
+func TestJob_RunNowAsync(t *testing.T) {
+	errFailed := errors.New("failed")
+	s := NewScheduler(time.UTC)
+	job, err := s.Every(1).Hour().Do(func() error {
+		time.Sleep(100 * time.Millisecond)
+		return errFailed
+	})
+	require.NoError(t, err)
+
+	result := <-job.RunNowAsync()
+	assert.Equal(t, errFailed, result.Err)
+	assert.Equal(t, job, result.Job)
+	assert.Equal(t, 1, job.RunCount())
+
+	t.Run("a coalesced trigger receives the in-flight result", func(t *testing.T) {
+		job.SingletonMode()
+		first := job.RunNowAsync()
+		time.Sleep(10 * time.Millisecond)
+		second := job.RunNowAsync()
+		assert.Equal(t, errFailed, (<-first).Err)
+		assert.Equal(t, errFailed, (<-second).Err)
+		assert.Equal(t, 2, job.RunCount())
+		assert.Equal(t, 1, job.CoalescedRuns())
+	})
+}
+
+func TestJob_Ordered(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	var running, overlaps int32
+	job, err := s.Every(1).Second().Do(func() {
+		if atomic.AddInt32(&running, 1) > 1 {
+			atomic.AddInt32(&overlaps, 1)
+		}
+		time.Sleep(50 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	})
+	require.NoError(t, err)
+	job.Ordered()
+	assert.Equal(t, OrderedMode, job.Mode())
+
+	// the triggers take their places as they arrive, whenever their goroutines run
+	finished := make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		i := i
+		require.NoError(t, s.runThen(job, func() { finished <- i }))
+	}
+	order := []int{<-finished, <-finished, <-finished}
+	assert.Equal(t, []int{0, 1, 2}, order)
+	assert.Equal(t, 3, job.RunCount())
+	assert.Zero(t, atomic.LoadInt32(&overlaps))
+
+	t.Run("triggers beyond the queue are skipped", func(t *testing.T) {
+		job.Ordered(WithMaxQueue(1))
+		for i := 0; i < 3; i++ {
+			require.NoError(t, s.run(job))
+		}
+		s.runningJobs.Wait()
+		assert.Equal(t, 5, job.RunCount())
+		assert.Equal(t, 1, job.SkippedRuns())
+	})
+}
+
+func TestJob_AbortCurrentRun(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	var aborts int32
+	job, err := s.Every(1).Hour().Do(func(ctx context.Context, d time.Duration) {
+		select {
+		case <-ctx.Done():
+			atomic.AddInt32(&aborts, 1)
+		case <-time.After(d):
+		}
+	}, time.Second)
+	require.NoError(t, err)
+
+	result := job.RunNowAsync()
+	time.Sleep(50 * time.Millisecond)
+	job.AbortCurrentRun()
+	select {
+	case r := <-result:
+		assert.Equal(t, ErrAborted, r.Err)
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("the run was not aborted")
+	}
+	assert.Equal(t, ErrAborted, job.Err())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&aborts))
+
+	t.Run("the next run gets a new context", func(t *testing.T) {
+		job, err := s.Every(1).Hour().Do(func(ctx context.Context) error { return ctx.Err() })
+		require.NoError(t, err)
+		job.AbortCurrentRun()
+		assert.NoError(t, (<-job.RunNowAsync()).Err)
+	})
+}
+
+func TestJob_TryRunNow(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	release := make(chan struct{})
+	job, err := s.Every(1).Hour().Do(func() { <-release })
+	require.NoError(t, err)
+
+	assert.True(t, job.TryRunNow())
+	assert.False(t, job.TryRunNow(), "a run is in flight")
+	close(release)
+	require.NoError(t, job.WaitUntil(context.Background(), func() bool { return job.TryRunNow() }, 10*time.Millisecond))
+	require.NoError(t, job.WaitUntil(context.Background(), func() bool { return job.RunCount() == 2 }, 10*time.Millisecond))
+
+	t.Run("the rate limit is exhausted", func(t *testing.T) {
+		job, err := s.Every(1).Hour().Do(func() {})
+		require.NoError(t, err)
+		require.NoError(t, job.RateLimit(1, time.Hour).Err())
+		require.NoError(t, job.run())
+		assert.False(t, job.TryRunNow())
+		assert.Equal(t, 1, job.RunCount())
+	})
+}
+
+func TestJob_LastSuccessfulRun(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+	var fail bool
+	job, err := s.Every(1).Minute().Do(func() error {
+		if fail {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	job.Sync()
+	assert.True(t, job.LastSuccessfulRun().IsZero())
+
+	require.NoError(t, s.run(job))
+	assert.Equal(t, start, job.LastSuccessfulRun())
+
+	fail = true
+	now = start.Add(time.Minute)
+	require.NoError(t, s.run(job))
+	assert.Equal(t, now, job.LastRun())
+	assert.Equal(t, start, job.LastSuccessfulRun(), "a failed run should not advance the last successful run")
+
+	fail = false
+	now = start.Add(2 * time.Minute)
+	require.NoError(t, s.run(job))
+	assert.Equal(t, now, job.LastSuccessfulRun())
+}
+
+func TestJob_CoalesceMissed(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+	var runs int
+	job, err := s.Every(1).Minute().Do(func() { runs++ })
+	require.NoError(t, err)
+	job.Sync()
+	job.CoalesceMissed()
+	job.setLastRun(now)
+	s.scheduleNextRun(job)
+
+	// the runs at 00:01 to 00:05 are missed
+	now = start.Add(5*time.Minute + 30*time.Second)
+	s.RunPending()
+	s.RunPending()
+	assert.Equal(t, 1, runs, "the missed runs should be caught up with a single run")
+	assert.Equal(t, 4, job.CoalescedRuns())
+	assert.Equal(t, now.Add(time.Minute), job.NextRun())
+
+	job.Pause()
+	now = now.Add(3 * time.Minute)
+	s.RunPending()
+	assert.Equal(t, 1, runs)
+	assert.Zero(t, job.SkippedRuns(), "the runs missed while paused should be held")
+
+	job.Resume()
+	s.RunPending()
+	assert.Equal(t, 2, runs)
+	assert.Equal(t, 6, job.CoalescedRuns())
+	assert.Equal(t, now.Add(time.Minute), job.NextRun())
+
+	// the scheduler restarted after the runs at 00:09:30 and 00:10:30 were missed
+	now = now.Add(2*time.Minute + 30*time.Second)
+	s.scheduleAllJobs()
+	s.RunPending()
+	assert.Equal(t, 3, runs)
+	assert.Equal(t, 7, job.CoalescedRuns())
+
+	t.Run("monthly", func(t *testing.T) {
+		now = start
+		var runs int
+		job, err := s.Every(1).Month(30).Do(func() { runs++ })
+		require.NoError(t, err)
+		job.Sync()
+		job.CoalesceMissed()
+		job.setLastRun(now)
+		s.scheduleNextRun(job)
+		require.Equal(t, time.Date(2020, time.January, 30, 0, 0, 0, 0, time.UTC), job.NextRun())
+
+		// the runs on January 30th, March 1st and March 30th are missed
+		now = time.Date(2020, time.April, 10, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, s.runAndReschedule(job))
+		assert.Equal(t, 1, runs)
+		assert.Equal(t, 2, job.CoalescedRuns())
+		assert.Equal(t, time.Date(2020, time.April, 30, 0, 0, 0, 0, time.UTC), job.NextRun())
+	})
+}
+
+func TestJob_OnErrorReschedule(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+	fail := true
+	job, err := s.Every(1).Hour().Do(func() error {
+		if fail {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	job.Sync()
+	job.OnErrorReschedule(1, "minutes")
+	require.NoError(t, job.Err())
+	job.setLastRun(now)
+	s.scheduleNextRun(job)
+	assert.Equal(t, start.Add(time.Hour), job.NextRun())
+
+	for i := 0; i < 2; i++ {
+		now = job.NextRun()
+		require.NoError(t, s.runAndReschedule(job))
+		assert.Equal(t, now.Add(time.Minute), job.NextRun(), "a failed run should switch to the error interval")
+	}
+
+	fail = false
+	now = job.NextRun()
+	require.NoError(t, s.runAndReschedule(job))
+	assert.Equal(t, now.Add(time.Hour), job.NextRun(), "a successful run should restore the interval")
+
+	t.Run("configuration errors", func(t *testing.T) {
+		now = start
+		job, err := s.Every(1).Hour().Do(func() error { return nil })
+		require.NoError(t, err)
+		job.OnErrorReschedule(1, "minutes")
+		assert.Equal(t, ErrTriggerCycle, job.TriggerOnCompletionOf(job).Err())
+		job.setLastRun(now)
+		s.scheduleNextRun(job)
+		assert.Equal(t, start.Add(time.Hour), job.NextRun(), "only a failed run should switch to the error interval")
+
+	})
+
+	t.Run("successful async run", func(t *testing.T) {
+		var mu sync.Mutex
+		clock := start
+		s := NewScheduler(time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return clock
+		}}
+		release := make(chan struct{})
+		job, err := s.Every(1).Hour().Do(func() {
+			<-release
+			// the run takes a second
+			mu.Lock()
+			defer mu.Unlock()
+			clock = clock.Add(time.Second)
+		})
+		require.NoError(t, err)
+		job.OnErrorReschedule(1, "minutes")
+		job.setLastRun(start)
+		s.scheduleNextRun(job)
+		require.NoError(t, s.runAndReschedule(job))
+		close(release)
+		s.runningJobs.Wait()
+		assert.Equal(t, start.Add(time.Hour), job.NextRun(), "a successful run shouldn't be rescheduled once it completed")
+	})
+
+	t.Run("invalid interval", func(t *testing.T) {
+		job, err := s.Every(1).Hour().Do(func() {})
+		require.NoError(t, err)
+		assert.Equal(t, ErrZeroInterval, job.OnErrorReschedule(0, "minutes").Err())
+		assert.Equal(t, ErrUnknownTimeUnit, job.OnErrorReschedule(1, "fortnights").Err())
+	})
+}
+
+func TestJob_TimesPer(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+	job, err := s.Every(1).Hour().Do(func() {})
+	require.NoError(t, err)
+	job.TimesPer(4, "hours")
+	require.NoError(t, job.Err())
+	job.setLastRun(now)
+	s.scheduleNextRun(job)
+	assert.Equal(t, start.Add(15*time.Minute), job.NextRun())
+	assert.Equal(t, "every 15 minutes", job.describeSchedule())
+
+	t.Run("largest whole unit", func(t *testing.T) {
+		tests := []struct {
+			n        int
+			unit     string
+			interval jobInterval
+			timeUnit timeUnit
+		}{
+			{3, "days", 8, hours},
+			{7, "weeks", 24, hours},
+			{40, "hours", 90, seconds},
+			{60, "minutes", 1, seconds},
+		}
+		for _, tt := range tests {
+			interval, timeUnit, err := timesPer(tt.n, tt.unit)
+			require.NoError(t, err)
+			assert.Equal(t, tt.interval, interval, "%d per %s", tt.n, tt.unit)
+			assert.Equal(t, tt.timeUnit, timeUnit, "%d per %s", tt.n, tt.unit)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		job, err := s.Every(1).Hour().Do(func() {})
+		require.NoError(t, err)
+		assert.Equal(t, ErrInvalidTimesPer, job.TimesPer(0, "hours").Err())
+		assert.Equal(t, ErrInvalidTimesPer, job.TimesPer(-1, "hours").Err())
+		assert.Equal(t, ErrInvalidTimesPer, job.TimesPer(7, "minutes").Err())
+		assert.Equal(t, ErrInvalidTimesPer, job.TimesPer(2, "months").Err())
+		assert.Equal(t, ErrUnknownTimeUnit, job.TimesPer(2, "fortnights").Err())
+	})
+}
+
+func TestJob_MissingFunc(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	job, err := s.Every(1).Second().Do(task)
+	require.NoError(t, err)
+	job.Lock()
+	job.jobFunc = "missing"
+	job.Unlock()
+
+	assert.NotPanics(t, func() {
+		assert.Equal(t, ErrFuncNotFound, job.run())
+	})
+	assert.Equal(t, ErrFuncNotFound, job.Err())
+	assert.False(t, s.shouldRun(job))
+	assert.True(t, errors.Is(s.validateJob(job), ErrFuncNotFound))
+}
+
+func TestJob_OverlapCount(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	release := make(chan struct{})
+	f, started, _, _ := slowJob(release)
+	job, err := s.Every(1).Second().Do(f)
+	require.NoError(t, err)
+
+	require.NoError(t, s.run(job))
+	<-started
+	require.NoError(t, s.run(job))
+	<-started
+	assert.Equal(t, 1, job.OverlapCount())
+	close(release)
+	s.runningJobs.Wait()
+
+	require.NoError(t, s.run(job))
+	s.runningJobs.Wait()
+	assert.Equal(t, 1, job.OverlapCount(), "a run starting after the previous one completed doesn't overlap")
+
+	t.Run("no overlap in SingletonMode", func(t *testing.T) {
+		release := make(chan struct{})
+		f, started, _, _ := slowJob(release)
+		job, err := s.Every(1).Second().Do(f)
+		require.NoError(t, err)
+		job.SingletonMode()
+		require.NoError(t, s.run(job))
+		<-started
+		require.NoError(t, s.run(job))
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		s.runningJobs.Wait()
+		assert.Zero(t, job.OverlapCount())
+	})
+}
+
+func TestJob_TriggerOnCompletionOf(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	fail := false
+	a, err := s.Every(1).Hour().Do(func() error {
+		if fail {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	a.Sync()
+	var bRuns int
+	b, err := s.Every(1).Day().Do(func() { bRuns++ })
+	require.NoError(t, err)
+	b.Sync()
+	require.NoError(t, b.TriggerOnCompletionOf(a).TriggerOnCompletionOf(a).Err())
+	nextRun := b.NextRun()
+
+	require.NoError(t, s.run(a))
+	require.NoError(t, s.run(a))
+	assert.Equal(t, 2, bRuns, "b should run once after each successful run of a")
+	assert.Equal(t, nextRun, b.NextRun(), "b's own schedule should be left untouched")
+
+	fail = true
+	require.NoError(t, s.run(a))
+	assert.Equal(t, 2, bRuns, "a failed run shouldn't trigger b")
+
+	t.Run("cycles", func(t *testing.T) {
+		c, err := s.Every(1).Hour().Do(func() {})
+		require.NoError(t, err)
+		c.TriggerOnCompletionOf(b)
+		assert.Equal(t, ErrTriggerCycle, a.TriggerOnCompletionOf(c).Err())
+		assert.Equal(t, ErrTriggerCycle, c.TriggerOnCompletionOf(c).Err())
+	})
+
+	t.Run("concurrent cycles", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			x, err := s.Every(1).Hour().Do(func() {})
+			require.NoError(t, err)
+			y, err := s.Every(1).Hour().Do(func() {})
+			require.NoError(t, err)
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				x.TriggerOnCompletionOf(y)
+			}()
+			go func() {
+				defer wg.Done()
+				y.TriggerOnCompletionOf(x)
+			}()
+			wg.Wait()
+			assert.False(t, x.triggersJob(x), "the triggers shouldn't form a cycle")
+			s.RemoveByReference(x)
+			s.RemoveByReference(y)
+		}
+	})
+}
+
+func TestJob_OnComplete(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	var events []string
+	job, err := s.Every(1).Minute().Do(func() { events = append(events, "run") })
+	require.NoError(t, err)
+	job.Sync()
+	job.LimitRunsTo(3)
+	job.OnComplete(func() { events = append(events, "complete") })
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, s.run(job))
+	}
+	assert.Equal(t, []string{"run", "run"}, events)
+	require.NoError(t, s.run(job))
+	assert.Equal(t, []string{"run", "run", "run", "complete"}, events)
+	require.NoError(t, s.run(job))
+	assert.Equal(t, []string{"run", "run", "run", "complete", "run"}, events, "the job should complete once")
+}
+
+func TestJob_ErrorCount(t *testing.T) {
+	errFailed := errors.New("failed")
+	s := NewScheduler(time.UTC)
+	var fail bool
+	job, err := s.Every(1).Minute().Do(func() error {
+		if fail {
+			return errFailed
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	job.Sync()
+
+	require.NoError(t, s.run(job))
+	assert.Equal(t, 0, job.ErrorCount())
+	assert.NoError(t, job.LastError())
+
+	fail = true
+	require.NoError(t, s.run(job))
+	require.NoError(t, s.run(job))
+	assert.Equal(t, 2, job.ErrorCount())
+	assert.Equal(t, errFailed, job.LastError())
+
+	fail = false
+	require.NoError(t, s.run(job))
+	assert.Equal(t, 2, job.ErrorCount(), "a successful run should not count as an error")
+	assert.Equal(t, 4, job.RunCount())
+
+	job.ResetRunCount()
+	assert.Equal(t, 0, job.ErrorCount())
+	assert.Equal(t, 0, job.RunCount())
+}
+
+func TestJob_WaitForNextRun(t *testing.T) {
+	errFailed := errors.New("failed")
+	s := NewScheduler(time.UTC)
+	job, err := s.Every(1).Hour().Do(func() error { return errFailed })
+	require.NoError(t, err)
+
+	_, err = job.WaitForNextRun(50 * time.Millisecond)
+	assert.Equal(t, ErrWaitTimeout, err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		job.run()
+	}()
+	result, err := job.WaitForNextRun(time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, job, result.Job)
+	assert.Equal(t, errFailed, result.Err)
+}
+
+func TestJob_Timer(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	job, _ := s.Every(1).Minute().Do(task)
+	job.setNextRun(time.Now().Add(100 * time.Millisecond))
+
+	timer := job.Timer()
+	select {
+	case <-timer.C:
+		assert.False(t, time.Now().Before(job.NextRun()), "the timer should not fire before the next run")
+	case <-time.After(time.Second):
+		t.Fatal("the timer did not fire at the next run")
+	}
+	assert.Zero(t, job.RunCount(), "the timer should not run the job")
+}
+
 func TestGetScheduledTime(t *testing.T) {
 	j, _ := NewScheduler(time.UTC).Every(1).Minute().At("10:30").Do(task)
 	assert.Equal(t, "10:30", j.ScheduledAtTime())
@@ -127,6 +814,54 @@ func TestJob_LimitRunsTo(t *testing.T) {
 	assert.Equal(t, j.shouldRun(), false, "Not expecting it to run again")
 }
 
+func TestJob_RunConfigAccessors(t *testing.T) {
+	j, _ := NewScheduler(time.UTC).Every(1).Second().Do(task)
+	assert.False(t, j.IsFiniteRuns())
+	assert.Zero(t, j.MaxRuns())
+	assert.Equal(t, NoMode, j.Mode())
+
+	j.LimitRunsTo(3)
+	j.SingletonMode()
+	assert.True(t, j.IsFiniteRuns())
+	assert.Equal(t, 3, j.MaxRuns())
+	assert.Equal(t, SingletonMode, j.Mode())
+}
+
+func TestJob_SetMode(t *testing.T) {
+	j, _ := NewScheduler(time.UTC).Every(1).Second().Do(func() {
+		time.Sleep(100 * time.Millisecond)
+	})
+	runConcurrently := func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				j.run()
+			}()
+			time.Sleep(10 * time.Millisecond)
+		}
+		wg.Wait()
+	}
+
+	j.SetMode(SingletonMode)
+	assert.Equal(t, SingletonMode, j.Mode())
+	runConcurrently()
+	assert.Equal(t, 1, j.RunCount(), "overlapping triggers share the run in SingletonMode")
+
+	j.SetMode(NoMode)
+	assert.Equal(t, NoMode, j.Mode())
+	runConcurrently()
+	assert.Equal(t, 4, j.RunCount(), "overlapping triggers run in NoMode")
+}
+
+func TestMode_String(t *testing.T) {
+	assert.Equal(t, "NoMode", NoMode.String())
+	assert.Equal(t, "SingletonMode", SingletonMode.String())
+	assert.Equal(t, "OrderedMode", OrderedMode.String())
+	assert.Equal(t, "Mode(7)", Mode(7).String())
+}
+
 func TestJob_CommonExports(t *testing.T) {
 	s := NewScheduler(time.Local)
 	j, _ := s.Every(1).Second().Do(func() {})
@@ -144,3 +879,301 @@ func TestJob_CommonExports(t *testing.T) {
 	j.lastRun = lastRun
 	assert.Equal(t, lastRun, j.LastRun())
 }
+
+func TestJob_Then(t *testing.T) {
+	errStep := errors.New("step two failed")
+	testCases := []struct {
+		desc            string
+		continueOnError bool
+		expectedSteps   []string
+	}{
+		{"stops at the first error", false, []string{"one", "two"}},
+		{"continues on error", true, []string{"one", "two", "three"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var steps []string
+			step := func(name string) {
+				steps = append(steps, name)
+			}
+			j, err := NewScheduler(time.UTC).Every(1).Minute().Do(step, "one")
+			require.NoError(t, err)
+			j.Then(func(name string) error {
+				step(name)
+				return errStep
+			}, "two").Then(step, "three")
+			if tc.continueOnError {
+				j.ContinueOnError()
+			}
+
+			j.run()
+			assert.Equal(t, tc.expectedSteps, steps)
+			assert.Equal(t, errStep, j.Err())
+			assert.Equal(t, 1, j.RunCount(), "a run counts once regardless of the number of steps")
+		})
+	}
+
+	t.Run("chaining a non function", func(t *testing.T) {
+		j, err := NewScheduler(time.UTC).Every(1).Minute().Do(task)
+		require.NoError(t, err)
+		j.Then(1)
+		assert.Equal(t, ErrNotAFunction, j.Err())
+	})
+}
+
+func TestTimeWindow_Contains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2020, time.January, 1, hour, minute, 0, 0, time.UTC)
+	}
+	testCases := []struct {
+		desc     string
+		start    string
+		end      string
+		t        time.Time
+		expected bool
+	}{
+		{"within", "09:00", "17:00", at(12, 0), true},
+		{"at start", "09:00", "17:00", at(9, 0), true},
+		{"at end", "09:00", "17:00", at(17, 0), true},
+		{"before", "09:00", "17:00", at(8, 59), false},
+		{"after", "09:00", "17:00", at(17, 1), false},
+		{"spanning midnight, late", "22:00", "06:00", at(23, 0), true},
+		{"spanning midnight, early", "22:00", "06:00", at(5, 0), true},
+		{"spanning midnight, outside", "22:00", "06:00", at(12, 0), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			window, err := newTimeWindow(tc.start, tc.end)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, window.contains(tc.t))
+		})
+	}
+}
+
+func TestJob_BetweenBadFormat(t *testing.T) {
+	j, _ := NewScheduler(time.UTC).Every(10).Minutes().Do(task)
+	j.Between("09:00", "25:00")
+	assert.Equal(t, ErrTimeFormat, j.Err())
+}
+
+func TestJob_RateLimit(t *testing.T) {
+	t.Run("triggers exceeding the budget are skipped", func(t *testing.T) {
+		var runs int
+		s := NewScheduler(time.UTC)
+		job, err := s.Every(1).Second().Do(func() { runs++ })
+		require.NoError(t, err)
+		job.RateLimit(2, time.Minute)
+		var reasons []string
+		job.OnSkip(func(reason string) { reasons = append(reasons, reason) })
+
+		for i := 0; i < 5; i++ {
+			job.run()
+		}
+		assert.Equal(t, 2, runs)
+		assert.Equal(t, 3, job.SkippedRuns())
+		assert.Equal(t, []string{SkipReasonRateLimited, SkipReasonRateLimited, SkipReasonRateLimited}, reasons)
+	})
+
+	t.Run("refilled on the clock of the scheduler", func(t *testing.T) {
+		now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+		s := NewScheduler(time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+		job, err := s.Every(1).Second().Do(task)
+		require.NoError(t, err)
+		job.Sync()
+		job.RateLimit(1, time.Minute)
+
+		require.NoError(t, s.run(job))
+		require.NoError(t, s.run(job))
+		assert.Equal(t, 1, job.RunCount())
+		now = now.Add(time.Minute)
+		require.NoError(t, s.run(job))
+		assert.Equal(t, 2, job.RunCount())
+		assert.Equal(t, 1, job.SkippedRuns())
+	})
+
+	t.Run("invalid limits", func(t *testing.T) {
+		assert.Equal(t, ErrInvalidRateLimit, NewJob(1).RateLimit(0, time.Minute).Err())
+		assert.Equal(t, ErrInvalidRateLimit, NewJob(1).RateLimit(1, 0).Err())
+	})
+}
+
+func TestJob_Clone(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	var runs []string
+	job, err := s.Every(2).Monday().At("10:30").Do(func(name string) { runs = append(runs, name) }, "original")
+	require.NoError(t, err)
+	job.Tag("tag")
+	job.SetLabel("env", "dev")
+	job.LimitRunsTo(3)
+	job.run()
+
+	clone := job.Clone()
+	assert.Equal(t, job.ScheduledAtTime(), clone.ScheduledAtTime())
+	assert.Equal(t, job.Weekdays(), clone.Weekdays())
+	assert.Equal(t, job.interval, clone.interval)
+	assert.Equal(t, job.MaxRuns(), clone.MaxRuns())
+	assert.Equal(t, job.Tags(), clone.Tags())
+	assert.Equal(t, job.Labels(), clone.Labels())
+	assert.Equal(t, 0, clone.RunCount())
+	assert.True(t, clone.LastRun().IsZero())
+	assert.True(t, clone.NextRun().IsZero())
+
+	clone.fparams[clone.jobFunc][0] = "clone"
+	clone.Tag("other")
+	clone.SetLabel("env", "prod")
+	clone.scheduledWeekdays[0] = time.Friday
+	clone.run()
+
+	assert.Equal(t, []string{"original", "clone"}, runs)
+	assert.Equal(t, 1, job.RunCount())
+	assert.Equal(t, []string{"tag"}, job.Tags())
+	assert.Equal(t, map[string]string{"env": "dev"}, job.Labels())
+	weekday, err := job.Weekday()
+	require.NoError(t, err)
+	assert.Equal(t, time.Monday, weekday)
+}
+
+func TestJob_WarmUp(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	var calls int
+	job, err := s.Every(1).Minute().Do(func() { calls++ })
+	require.NoError(t, err)
+	job.Sync()
+	job.LimitRunsTo(1)
+	job.WarmUp()
+
+	s.scheduleAllJobs()
+	assert.Equal(t, 1, calls, "the warm-up run happens when the job is scheduled")
+	assert.Equal(t, 0, job.RunCount(), "the warm-up run doesn't count as a run")
+	assert.Equal(t, now, job.NextRun(), "the warm-up run doesn't delay the first run")
+
+	for i := 0; i < 3; i++ {
+		s.RunPending()
+		now = now.Add(time.Minute)
+	}
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 1, job.RunCount())
+}
+
+func TestJob_RunCountSaturates(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	job, err := s.Every(1).Second().Do(task)
+	require.NoError(t, err)
+	job.LimitRunsTo(maxRunCount)
+	job.setRunCount(maxRunCount - 2)
+
+	for i := 0; i < 5; i++ {
+		job.run()
+		assert.True(t, job.RunCount() > 0, "the run count must not wrap")
+	}
+	assert.Equal(t, maxRunCount, job.RunCount())
+	assert.False(t, job.shouldRun(), "a finite job must not run again once the count is saturated")
+}
+
+func TestJob_AfterRuns(t *testing.T) {
+	t.Run("the interval changes after the 5th run", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+		job, err := s.Every(10).Seconds().Do(task)
+		require.NoError(t, err)
+		job.Sync()
+		require.NoError(t, job.AfterRuns(5).ChangeTo(5, "minutes").Err())
+
+		s.scheduleNextRun(job)
+		var delays []time.Duration
+		for i := 0; i < 7; i++ {
+			now = job.NextRun()
+			require.NoError(t, s.runAndReschedule(job))
+			delays = append(delays, job.NextRun().Sub(now))
+		}
+		assert.Equal(t, []time.Duration{
+			10 * time.Second, 10 * time.Second, 10 * time.Second, 10 * time.Second,
+			5 * time.Minute, 5 * time.Minute, 5 * time.Minute,
+		}, delays)
+		assert.Equal(t, "minutes", job.Unit())
+	})
+
+	t.Run("invalid schedules", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		job, err := s.Every(10).Seconds().Do(task)
+		require.NoError(t, err)
+		assert.Equal(t, ErrUnknownTimeUnit, job.AfterRuns(5).ChangeTo(5, "fortnights").Err())
+	})
+}
+
+func TestJob_ExplainNextRun(t *testing.T) {
+	// January 6th 2020 is a Monday
+	monday := time.Date(2020, time.January, 6, 10, 0, 0, 0, time.UTC)
+
+	t.Run("anchoring and jitter", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return monday }}
+		s.SetRandSource(rand.NewSource(42))
+		job, err := s.Every(1).Monday().At("09:00").Jitter(time.Minute).Do(task)
+		require.NoError(t, err)
+		assert.Equal(t, "not scheduled", job.ExplainNextRun())
+
+		s.scheduleNextRun(job)
+		jitter := job.NextRun().Sub(monday.AddDate(0, 0, 7).Add(-time.Hour))
+		assert.Equal(t, fmt.Sprintf("nextRun=%s, every 1 weeks, weekdays=Monday, atTimes=09:00, jitter=+%s",
+			job.NextRun().Format(time.RFC3339), jitter), job.ExplainNextRun())
+	})
+
+	t.Run("immediate start", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return monday }}
+		job, err := s.Every(1).Hour().Do(task)
+		require.NoError(t, err)
+
+		s.scheduleNextRun(job)
+		assert.Equal(t, "nextRun=2020-01-06T10:00:00Z, starts immediately", job.ExplainNextRun())
+	})
+
+	t.Run("constraints", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return monday }}
+		job, err := s.Every(1).Days().Do(task)
+		require.NoError(t, err)
+		job.ExcludeDates(monday.AddDate(0, 0, 1))
+		job.setLastRun(monday)
+
+		s.scheduleNextRun(job)
+		assert.Equal(t, "nextRun=2020-01-08T00:00:00Z, every 1 days, moved from 2020-01-07T00:00:00Z by the windows or excluded dates", job.ExplainNextRun())
+	})
+}
+
+func TestJob_WaitUntil(t *testing.T) {
+	job := NewJob(1)
+
+	t.Run("returns once the condition is true", func(t *testing.T) {
+		var checks int
+		err := job.WaitUntil(context.Background(), func() bool {
+			checks++
+			return checks == 3
+		}, time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, 3, checks)
+	})
+
+	t.Run("cancellation unblocks the wait", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			done <- job.WaitUntil(ctx, func() bool { return false }, time.Hour)
+		}()
+		cancel()
+		select {
+		case err := <-done:
+			assert.Equal(t, context.Canceled, err)
+		case <-time.After(time.Second):
+			t.Fatal("the wait was not unblocked by the cancellation")
+		}
+	})
+}
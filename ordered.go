@@ -0,0 +1,85 @@
+package gocron
+
+import "sync"
+
+// defaultOrderedQueue is the number of triggers of a Job in OrderedMode
+// which can wait for the running one, unless set with WithMaxQueue
+const defaultOrderedQueue = 100
+
+// orderedQueue runs the triggers of a Job one at a time, in the order
+// they arrived in
+type orderedQueue struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	next     uint64          // ticket of the next trigger to arrive
+	serving  uint64          // ticket of the trigger allowed to run
+	released map[uint64]bool // tickets released before their turn
+}
+
+func newOrderedQueue() *orderedQueue {
+	q := &orderedQueue{released: make(map[uint64]bool)}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// orderedTicket is the place of a trigger in an orderedQueue
+type orderedTicket struct {
+	queue    *orderedQueue
+	number   uint64
+	ok       bool // false if the queue was full when the trigger arrived
+	released bool
+}
+
+// take gives the arriving trigger its place in the queue. The ticket
+// isn't ok if capacity triggers are already waiting
+func (q *orderedQueue) take(capacity int) *orderedTicket {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	// the trigger being served doesn't count against the capacity
+	if int(q.next-q.serving) > capacity {
+		return &orderedTicket{queue: q}
+	}
+	ticket := &orderedTicket{queue: q, number: q.next, ok: true}
+	q.next++
+	return ticket
+}
+
+// wait blocks until the turn of the trigger comes. It returns false
+// without waiting if the ticket isn't ok
+func (t *orderedTicket) wait() bool {
+	if !t.ok {
+		return false
+	}
+	q := t.queue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.serving != t.number && !t.released {
+		q.cond.Wait()
+	}
+	return !t.released
+}
+
+// release gives up the place of the trigger, once it ran or if it
+// won't run, letting the next one run. Releasing again does nothing
+func (t *orderedTicket) release() {
+	if !t.ok {
+		return
+	}
+	q := t.queue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if t.released {
+		return
+	}
+	t.released = true
+	if q.serving != t.number {
+		q.released[t.number] = true
+		return
+	}
+	q.serving++
+	for q.released[q.serving] {
+		delete(q.released, q.serving)
+		q.serving++
+	}
+	q.cond.Broadcast()
+}
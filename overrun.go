@@ -0,0 +1,50 @@
+package gocron
+
+import "fmt"
+
+// OverrunPolicy is the behavior of the Scheduler when a Job is triggered
+// while its previous run is still in progress
+type OverrunPolicy int8
+
+const (
+	// AllowOverrun lets the runs of a Job overlap. This is the default
+	AllowOverrun OverrunPolicy = iota
+	// QueueOverrun makes an overrunning trigger wait for the runs in
+	// progress, then run, like in OrderedMode
+	QueueOverrun
+	// SkipOverrun skips an overrunning trigger, counted in SkippedRuns
+	SkipOverrun
+	// ExtendOverrun holds the next trigger until the run in progress
+	// completes, and schedules it from the completion of the run
+	ExtendOverrun
+)
+
+func (p OverrunPolicy) String() string {
+	switch p {
+	case AllowOverrun:
+		return "AllowOverrun"
+	case QueueOverrun:
+		return "QueueOverrun"
+	case SkipOverrun:
+		return "SkipOverrun"
+	case ExtendOverrun:
+		return "ExtendOverrun"
+	}
+	return fmt.Sprintf("OverrunPolicy(%d)", p)
+}
+
+// SetOverrunPolicy sets the behavior of the Scheduler for the jobs triggered
+// while their previous run is still in progress. QueueOverrun and SkipOverrun
+// apply to the jobs in NoMode only, as the jobs in SingletonMode or
+// OrderedMode already don't overlap. ExtendOverrun applies to all the jobs
+func (s *Scheduler) SetOverrunPolicy(policy OverrunPolicy) {
+	s.overrunMutex.Lock()
+	defer s.overrunMutex.Unlock()
+	s.overrunPolicy = policy
+}
+
+func (s *Scheduler) getOverrunPolicy() OverrunPolicy {
+	s.overrunMutex.RLock()
+	defer s.overrunMutex.RUnlock()
+	return s.overrunPolicy
+}
@@ -0,0 +1,161 @@
+package gocron
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowJob returns a job function blocking until release is closed, and
+// counting its runs and the runs in progress at the same time
+func slowJob(release chan struct{}) (f func(), started chan struct{}, runs, maxActive *int32) {
+	started = make(chan struct{}, 10)
+	runs, maxActive = new(int32), new(int32)
+	var active int32
+	f = func() {
+		atomic.AddInt32(runs, 1)
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			max := atomic.LoadInt32(maxActive)
+			if n <= max || atomic.CompareAndSwapInt32(maxActive, max, n) {
+				break
+			}
+		}
+		started <- struct{}{}
+		<-release
+	}
+	return f, started, runs, maxActive
+}
+
+func TestScheduler_SetOverrunPolicy(t *testing.T) {
+	t.Run("allow", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		release := make(chan struct{})
+		f, started, runs, maxActive := slowJob(release)
+		job, err := s.Every(1).Second().Do(f)
+		require.NoError(t, err)
+
+		require.NoError(t, s.run(job))
+		<-started
+		require.NoError(t, s.run(job))
+		<-started
+		close(release)
+		s.runningJobs.Wait()
+		assert.Equal(t, int32(2), atomic.LoadInt32(runs))
+		assert.Equal(t, int32(2), atomic.LoadInt32(maxActive))
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		s.SetOverrunPolicy(QueueOverrun)
+		release := make(chan struct{})
+		f, started, runs, maxActive := slowJob(release)
+		job, err := s.Every(1).Second().Do(f)
+		require.NoError(t, err)
+
+		require.NoError(t, s.run(job))
+		<-started
+		require.NoError(t, s.run(job))
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(1), atomic.LoadInt32(runs), "the trigger should wait for the run in progress")
+		close(release)
+		s.runningJobs.Wait()
+		assert.Equal(t, int32(2), atomic.LoadInt32(runs))
+		assert.Equal(t, int32(1), atomic.LoadInt32(maxActive))
+		assert.Equal(t, 0, job.SkippedRuns())
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		s.SetOverrunPolicy(SkipOverrun)
+		release := make(chan struct{})
+		f, started, runs, _ := slowJob(release)
+		job, err := s.Every(1).Second().Do(f)
+		require.NoError(t, err)
+		skipped := make(chan string, 1)
+		job.OnSkip(func(reason string) { skipped <- reason })
+
+		require.NoError(t, s.run(job))
+		<-started
+		require.NoError(t, s.run(job))
+		assert.Equal(t, SkipReasonRunning, <-skipped)
+		close(release)
+		s.runningJobs.Wait()
+		assert.Equal(t, int32(1), atomic.LoadInt32(runs))
+		assert.Equal(t, 1, job.SkippedRuns())
+
+		require.NoError(t, s.run(job))
+		s.runningJobs.Wait()
+		assert.Equal(t, int32(2), atomic.LoadInt32(runs), "the job should run again once the run completed")
+	})
+
+	t.Run("singleton mode takes precedence", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		s.SetOverrunPolicy(SkipOverrun)
+		release := make(chan struct{})
+		f, started, runs, _ := slowJob(release)
+		job, err := s.Every(1).Second().Do(f)
+		require.NoError(t, err)
+		job.SingletonMode()
+
+		require.NoError(t, s.run(job))
+		<-started
+		require.NoError(t, s.run(job))
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		s.runningJobs.Wait()
+		assert.Equal(t, int32(1), atomic.LoadInt32(runs))
+		assert.Equal(t, 0, job.SkippedRuns(), "the trigger should share the run in progress")
+	})
+
+	t.Run("extend", func(t *testing.T) {
+		now := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+		var mu sync.Mutex
+		clock := func() time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return now
+		}
+		setNow := func(t time.Time) {
+			mu.Lock()
+			defer mu.Unlock()
+			now = t
+		}
+		s := NewScheduler(time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return clock() }}
+		s.SetOverrunPolicy(ExtendOverrun)
+		release := make(chan struct{})
+		f, started, runs, _ := slowJob(release)
+		job, err := s.Every(1).Minute().Do(f)
+		require.NoError(t, err)
+		job.setLastRun(clock())
+		s.scheduleNextRun(job)
+
+		setNow(clock().Add(time.Minute))
+		require.True(t, s.shouldRun(job))
+		require.NoError(t, s.runAndReschedule(job))
+		<-started
+		setNow(clock().Add(5 * time.Minute))
+		assert.False(t, s.shouldRun(job), "the job shouldn't be triggered while running")
+
+		completedAt := clock()
+		close(release)
+		s.runningJobs.Wait()
+		assert.Equal(t, completedAt.Add(time.Minute), job.NextRun())
+		assert.Equal(t, int32(1), atomic.LoadInt32(runs))
+		assert.Equal(t, 0, job.SkippedRuns())
+	})
+}
+
+func TestOverrunPolicy_String(t *testing.T) {
+	assert.Equal(t, "AllowOverrun", AllowOverrun.String())
+	assert.Equal(t, "QueueOverrun", QueueOverrun.String())
+	assert.Equal(t, "SkipOverrun", SkipOverrun.String())
+	assert.Equal(t, "ExtendOverrun", ExtendOverrun.String())
+	assert.Equal(t, "OverrunPolicy(9)", OverrunPolicy(9).String())
+}
@@ -0,0 +1,62 @@
+package gocron
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenBucket allows bursts of up to capacity runs, refilled at a constant
+// rate of capacity tokens per period
+type tokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64   // tokens per second
+	last     time.Time // time of the last refill, on the clock of the caller
+}
+
+func newTokenBucket(n int, per time.Duration) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(n),
+		tokens:   float64(n),
+		rate:     float64(n) / per.Seconds(),
+	}
+}
+
+// take returns true and consumes a token if one is available at now
+func (b *tokenBucket) take(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// available returns true if a token is available at now, without consuming it
+func (b *tokenBucket) available(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	return b.tokens >= 1
+}
+
+// refill adds the tokens accumulated from the last refill to now
+func (b *tokenBucket) refill(now time.Time) {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+	}
+	b.last = now
+}
+
+// reset returns a full bucket with the same capacity and rate
+func (b *tokenBucket) reset() *tokenBucket {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return &tokenBucket{capacity: b.capacity, tokens: b.capacity, rate: b.rate}
+}
@@ -0,0 +1,44 @@
+package gocron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	b := newTokenBucket(3, time.Minute)
+
+	taken := 0
+	for i := 0; i < 10; i++ {
+		if b.take(now) {
+			taken++
+		}
+	}
+	assert.Equal(t, 3, taken, "a burst is limited to the capacity")
+
+	now = now.Add(20 * time.Second)
+	assert.True(t, b.take(now), "a token is refilled every 20 seconds")
+	assert.False(t, b.take(now))
+
+	now = now.Add(time.Hour)
+	taken = 0
+	for i := 0; i < 10; i++ {
+		if b.take(now) {
+			taken++
+		}
+	}
+	assert.Equal(t, 3, taken, "refilled tokens are capped to the capacity")
+
+	// one trigger per second over a minute stays within 3 + 3 runs
+	taken = 0
+	for i := 0; i < 60; i++ {
+		now = now.Add(time.Second)
+		if b.take(now) {
+			taken++
+		}
+	}
+	assert.Equal(t, 3, taken)
+}
@@ -0,0 +1,100 @@
+package gocron
+
+import "time"
+
+// resultsBufferSize is the number of results buffered by the channel returned by Results
+const resultsBufferSize = 100
+
+// RunResult describes a completed run of a Job
+type RunResult struct {
+	Job      *Job
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// Results returns a channel receiving the result of each Job run after the first
+// call to Results. The channel buffers up to 100 results, after which the oldest
+// result is dropped to make room for the newest, so that slow consumers never
+// block the Scheduler
+func (s *Scheduler) Results() <-chan RunResult {
+	s.resultsMutex.Lock()
+	defer s.resultsMutex.Unlock()
+	if s.results == nil {
+		s.results = make(chan RunResult, resultsBufferSize)
+	}
+	return s.results
+}
+
+func (s *Scheduler) emitResult(result RunResult) {
+	s.resultsMutex.Lock()
+	defer s.resultsMutex.Unlock()
+	if s.results == nil {
+		return
+	}
+	for {
+		select {
+		case s.results <- result:
+			return
+		default:
+			// drop the oldest result, which may race with a consumer
+			select {
+			case <-s.results:
+			default:
+			}
+		}
+	}
+}
+
+// signalRun wakes up the goroutines waiting for a run to complete
+func (s *Scheduler) signalRun() {
+	s.runSignalMutex.Lock()
+	defer s.runSignalMutex.Unlock()
+	if s.runSignal != nil {
+		close(s.runSignal)
+		s.runSignal = nil
+	}
+}
+
+func (s *Scheduler) getRunSignal() chan struct{} {
+	s.runSignalMutex.Lock()
+	defer s.runSignalMutex.Unlock()
+	if s.runSignal == nil {
+		s.runSignal = make(chan struct{})
+	}
+	return s.runSignal
+}
+
+// WaitForRuns blocks until every Job ran at least n times, or returns
+// ErrWaitTimeout if they didn't once the timeout elapsed. The Jobs without
+// a function, see ErrNoFuncSet, are ignored
+func (s *Scheduler) WaitForRuns(n int, timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		// get the signal before checking the run counts not to miss a run
+		signal := s.getRunSignal()
+		if s.allJobsRan(n) {
+			return nil
+		}
+		select {
+		case <-signal:
+		case <-timer.C:
+			return ErrWaitTimeout
+		}
+	}
+}
+
+func (s *Scheduler) allJobsRan(n int) bool {
+	// copy the jobs as the scheduler may be sorting them
+	s.jobsMutex.RLock()
+	jobs := append([]*Job(nil), s.jobs...)
+	s.jobsMutex.RUnlock()
+	for _, job := range jobs {
+		// the jobs without a function never run
+		if job.hasFunc() && job.RunCount() < n {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package gocron
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Results(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	errFailed := errors.New("failed")
+	ok, err := s.Every(1).Second().Do(func() {})
+	require.NoError(t, err)
+	ok.Sync()
+	failing, err := s.Every(1).Second().Do(func() error { return errFailed })
+	require.NoError(t, err)
+	failing.Sync()
+
+	results := s.Results()
+	s.RunAll()
+
+	got := map[*Job]error{}
+	for i := 0; i < 2; i++ {
+		select {
+		case result := <-results:
+			assert.False(t, result.Start.IsZero())
+			assert.True(t, result.Duration >= 0)
+			got[result.Job] = result.Err
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the results")
+		}
+	}
+	assert.Equal(t, map[*Job]error{ok: nil, failing: errFailed}, got)
+}
+
+func TestScheduler_ResultsDropOldest(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	results := s.Results()
+	for i := 0; i < resultsBufferSize+1; i++ {
+		s.emitResult(RunResult{Duration: time.Duration(i)})
+	}
+
+	assert.Len(t, results, resultsBufferSize)
+	assert.Equal(t, time.Duration(1), (<-results).Duration)
+}
+
+func TestScheduler_ResultsNotRequested(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	s.emitResult(RunResult{})
+	assert.Nil(t, s.results)
+}
+
+func TestScheduler_WaitForRuns(t *testing.T) {
+	t.Run("jobs of different intervals reach the run count", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		fast, err := s.Every(1).Second().Do(task)
+		require.NoError(t, err)
+		slow, err := s.Every(2).Seconds().Do(task)
+		require.NoError(t, err)
+		s.StartAsync()
+		defer s.Stop()
+
+		require.NoError(t, s.WaitForRuns(2, 5*time.Second))
+		assert.True(t, fast.RunCount() >= 2)
+		assert.True(t, slow.RunCount() >= 2)
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		_, err := s.Every(1).Hour().Do(task)
+		require.NoError(t, err)
+		s.StartAsync()
+		defer s.Stop()
+
+		assert.Equal(t, ErrWaitTimeout, s.WaitForRuns(2, 100*time.Millisecond))
+	})
+}
@@ -1,11 +1,14 @@
 package gocron
 
 import (
+	"fmt"
 	"math"
+	"math/rand"
 	"reflect"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,11 +23,79 @@ type Scheduler struct {
 
 	runningMutex sync.RWMutex
 	running      bool          // represents if the scheduler is running at the moment or not
+	startedAt    time.Time     // when the scheduler started running, zero if it's not
 	stopChan     chan struct{} // signal to stop scheduling
 
 	time timeWrapper // wrapper around time.Time
+
+	randMutex sync.Mutex
+	rand      *rand.Rand // source of randomness for jitter
+
+	runningJobs sync.WaitGroup // tracks the jobs running in their own goroutine
+
+	middlewaresMutex sync.RWMutex
+	middlewares      []Middleware
+
+	limiterMutex sync.RWMutex
+	limiter      *concurrencyLimiter // limits the number of jobs running at the same time
+
+	poolsMutex sync.Mutex
+	pools      map[string]*concurrencyLimiter // limit the runs of the jobs sharing a ResourcePool key
+
+	workersMutex sync.RWMutex
+	workers      *workerPool // runs the jobs on a fixed number of goroutines, if set
+
+	loggerMutex sync.RWMutex
+	logger      Logger // warns about the times of the day made ambiguous by a change of the clock
+
+	ambiguityMutex  sync.RWMutex
+	ambiguityPolicy AmbiguousTimePolicy // when to run at a time of the day repeated or skipped by the clock
+
+	staggerMutex   sync.RWMutex
+	startupStagger time.Duration // delay between the first runs of the jobs starting immediately
+
+	weekStartMutex sync.RWMutex
+	weekStart      *time.Weekday // first day of the weeks, Monday if nil
+
+	resultsMutex sync.Mutex
+	results      chan RunResult // results of the runs, created by Results
+
+	criticalMutex     sync.RWMutex
+	onCriticalFailure func(job *Job, err error) // called when a critical job fails, before stopping
+
+	lifecycleMutex sync.RWMutex
+	onStart        func() // called when the scheduler starts
+	onStop         func() // called when the scheduler stopped
+
+	strategyMutex sync.RWMutex
+	strategy      Strategy // computes the next runs, the interval based one if nil
+
+	scheduleMutex sync.RWMutex
+	lastJob       *Job  // job added by the last call to Schedule
+	scheduleErr   error // first error returned by Schedule
+
+	runSignalMutex sync.Mutex
+	runSignal      chan struct{} // closed after each run, created by the waiters
+
+	stats *runStats // counters of the runs, published by PublishExpvar
+
+	timeoutMutex   sync.RWMutex
+	defaultTimeout time.Duration // timeout of the jobs without their own
+
+	tracerMutex sync.RWMutex
+	tracer      Tracer // starts a span for each run, set with SetTracer
+
+	timeScaleMutex sync.RWMutex
+	timeScale      float64 // factor the intervals are divided by, 1 if unset
+
+	overrunMutex  sync.RWMutex
+	overrunPolicy OverrunPolicy // behavior for the jobs triggered while running
 }
 
+// Middleware wraps the execution of every job run by the Scheduler. It must
+// call next to continue the execution of the job
+type Middleware func(job *Job, next func())
+
 // NewScheduler creates a new Scheduler
 func NewScheduler(loc *time.Location) *Scheduler {
 	return &Scheduler{
@@ -33,31 +104,67 @@ func NewScheduler(loc *time.Location) *Scheduler {
 		running:  false,
 		stopChan: make(chan struct{}),
 		time:     &trueTime{},
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		stats:    &runStats{},
 	}
 }
 
+// SetRandSource sets the source of randomness used for jitter, allowing
+// reproducible schedules. The default source is seeded with the time
+// the Scheduler was created at
+func (s *Scheduler) SetRandSource(src rand.Source) {
+	s.randMutex.Lock()
+	defer s.randMutex.Unlock()
+	s.rand = rand.New(src)
+}
+
+// randDuration returns a random duration in [0, max)
+func (s *Scheduler) randDuration(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	s.randMutex.Lock()
+	defer s.randMutex.Unlock()
+	return time.Duration(s.rand.Int63n(int64(max)))
+}
+
 // StartBlocking starts all the pending jobs using a second-long ticker and blocks the current thread
 func (s *Scheduler) StartBlocking() {
 	<-s.StartAsync()
 }
 
-// StartAsync starts a goroutine that runs all the pending using a second-long ticker
+// StartAsync starts a goroutine that runs all the pending using a second-long ticker.
+// Calling StartAsync on a running Scheduler is a no-op returning the same channel
 func (s *Scheduler) StartAsync() chan struct{} {
-	if s.IsRunning() {
+	return s.start(true)
+}
+
+// start starts the scheduling goroutine, scheduling all the jobs beforehand if scheduleJobs is true
+func (s *Scheduler) start(scheduleJobs bool) chan struct{} {
+	// the running flag is only set here and cleared by stop, the scheduling
+	// goroutine of a previous start mustn't clear it once restarted
+	if s.swapRunning(true) {
 		return s.stopChan
 	}
-	s.setRunning(true)
+	s.notifyLifecycle(s.getOnStart())
 
-	s.scheduleAllJobs()
+	if scheduleJobs {
+		s.scheduleAllJobs()
+	}
 	ticker := s.time.NewTicker(1 * time.Second)
 	go func() {
+		lastTick, lastTickElapsed := s.time.Now(s.Location()), time.Now()
 		for {
 			select {
 			case <-ticker.C:
+				now := s.time.Now(s.Location())
+				if clockJumped(lastTick, now, time.Since(lastTickElapsed)) {
+					s.RecalculateNextRuns()
+				}
+				lastTick, lastTickElapsed = now, time.Now()
 				s.RunPending()
 			case <-s.stopChan:
 				ticker.Stop()
-				s.setRunning(false)
 				return
 			}
 		}
@@ -67,12 +174,46 @@ func (s *Scheduler) StartAsync() chan struct{} {
 }
 
 func (s *Scheduler) setRunning(b bool) {
+	s.swapRunning(b)
+}
+
+// swapRunning sets whether the scheduler is running and returns whether it
+// was, so that only one of concurrent callers changes it
+func (s *Scheduler) swapRunning(b bool) bool {
+	var startedAt time.Time
+	if b {
+		startedAt = s.time.Now(s.Location())
+	}
 	s.runningMutex.Lock()
 	defer s.runningMutex.Unlock()
-	s.running = b
+	was := s.running
+	if was != b {
+		s.running = b
+		s.startedAt = startedAt
+	}
+	return was
+}
+
+// StartedAt returns the time the scheduler started running at, or the
+// zero time if it's not running
+func (s *Scheduler) StartedAt() time.Time {
+	s.runningMutex.RLock()
+	defer s.runningMutex.RUnlock()
+	return s.startedAt
 }
 
-// IsRunning returns true if the scheduler is running
+// Uptime returns for how long the scheduler has been running, or zero
+// if it's not running
+func (s *Scheduler) Uptime() time.Duration {
+	startedAt := s.StartedAt()
+	if startedAt.IsZero() {
+		return 0
+	}
+	return s.time.Now(s.Location()).Sub(startedAt)
+}
+
+// IsRunning returns true if the scheduler is running, that is between
+// the calls to StartAsync (or StartBlocking) and Stop
 func (s *Scheduler) IsRunning() bool {
 	s.runningMutex.RLock()
 	defer s.runningMutex.RUnlock()
@@ -86,6 +227,20 @@ func (s *Scheduler) Jobs() []*Job {
 	return s.jobs
 }
 
+// ForEach calls f with each Job of the Scheduler, in order, until f returns
+// false. The Jobs are visited under the Scheduler's read lock without being
+// copied: f must not call the Scheduler methods adding, removing or sorting
+// Jobs, such as Every, Remove or NextRun, which would deadlock
+func (s *Scheduler) ForEach(f func(job *Job) bool) {
+	s.jobsMutex.RLock()
+	defer s.jobsMutex.RUnlock()
+	for _, job := range s.jobs {
+		if !f(job) {
+			return
+		}
+	}
+}
+
 func (s *Scheduler) setJobs(jobs []*Job) {
 	s.jobsMutex.Lock()
 	defer s.jobsMutex.Unlock()
@@ -128,41 +283,317 @@ func (s *Scheduler) Location() *time.Location {
 func (s *Scheduler) scheduleNextRun(job *Job) {
 	now := s.time.Now(s.Location())
 
-	if job.neverRan() {
-		if !job.NextRun().IsZero() {
+	firstRun := job.neverRan()
+	// the first run waits for the job's start delay
+	delay := job.getStartDelay()
+	start := now.Add(delay)
+	if firstRun {
+		job.startLifetime(now)
+		s.warmUp(job)
+		if nextRun := job.NextRun(); !nextRun.IsZero() {
+			if delay > 0 && nextRun.Before(start) {
+				job.setNextRun(start)
+			}
 			return // scheduled for future run and should skip scheduling
 		}
 		// default is for jobs to start immediately unless scheduled at a specific time or day
-		if job.getStartsImmediately() {
-			job.setNextRun(now)
+		if job.getStartsImmediately() && s.applyConstraints(job, start).Equal(start) {
+			job.setNextRun(start)
+			job.setNextRunExplanation(nextRunExplanation{source: "starts immediately"})
 			return
 		}
 	}
 
 	job.setLastRun(now)
+	job.applyScheduleChange()
+	nextRun := s.nextRunFrom(job, job.LastRun())
+	s.reportAmbiguousTimes(job, job.LastRun())
+	if firstRun && delay > 0 && nextRun.Before(start) {
+		nextRun = start
+	}
+	job.setNextRun(nextRun)
+}
+
+// warmUp runs the job's warm-up run if one is pending, bypassing the
+// middlewares and the limits applied to the regular runs
+func (s *Scheduler) warmUp(job *Job) {
+	if !job.takeWarmUp() {
+		return
+	}
+	if job.isSync() {
+		job.setErr(job.callFuncs(s.runOptions()))
+		return
+	}
+	s.runningJobs.Add(1)
+	go func() {
+		defer s.runningJobs.Done()
+		job.setErr(job.callFuncs(s.runOptions()))
+	}()
+}
+
+// nextRunFrom returns the job's next run as if it last ran at lastRun
+// and records how it was computed for ExplainNextRun
+func (s *Scheduler) nextRunFrom(job *Job, lastRun time.Time) time.Time {
+	explanation := nextRunExplanation{jitter: s.randDuration(job.getJitter())}
+	var nextRun time.Time
+	if errorInterval, ok := job.errorInterval(); ok {
+		nextRun = lastRun.Add(errorInterval)
+		explanation.source = "OnErrorReschedule"
+	} else if nextRunFunc := job.getNextRunFunc(); nextRunFunc != nil {
+		nextRun = nextRunFunc(lastRun)
+		explanation.source = "NextRunFunc"
+	}
+	if !nextRun.After(lastRun) {
+		strategy := s.getStrategy()
+		nextRun = strategy.Next(job, lastRun)
+		explanation.source = "strategy"
+		if _, ok := strategy.(intervalStrategy); ok {
+			nextRun = s.alignToPhase(job, lastRun, nextRun)
+			explanation.source = job.describeSchedule()
+		}
+	}
+	nextRun = nextRun.Add(explanation.jitter)
+	if onReschedule := job.getOnReschedule(); onReschedule != nil {
+		if adjusted := onReschedule(nextRun); !adjusted.IsZero() && !adjusted.Equal(nextRun) {
+			explanation.adjustedFrom = nextRun
+			nextRun = adjusted
+		}
+	}
+	constrained := s.applyConstraints(job, nextRun)
+	if !constrained.Equal(nextRun) {
+		explanation.constrainedFrom = nextRun
+	}
+	job.setNextRunExplanation(explanation)
+	return constrained
+}
+
+// alignToPhase returns the first time after lastRun at the job's phase
+// offset from the times its interval divides the day into, or nextRun if
+// the job has no phase or doesn't run at an interval of seconds, minutes
+// or hours
+func (s *Scheduler) alignToPhase(job *Job, lastRun, nextRun time.Time) time.Time {
+	offset, ok := job.getPhase()
+	if !ok {
+		return nextRun
+	}
+	switch job.unit {
+	case seconds, minutes, hours:
+	default:
+		return nextRun
+	}
+	interval := nextRun.Sub(lastRun)
+	if interval <= 0 {
+		return nextRun
+	}
+	offset %= interval
+	if offset < 0 {
+		offset += interval
+	}
+	midnight := s.roundToMidnight(lastRun)
+	aligned := midnight.Add(offset)
+	if !aligned.After(lastRun) {
+		aligned = aligned.Add((lastRun.Sub(aligned)/interval + 1) * interval)
+	}
+	// the times are counted again from the next midnight
+	if nextDay := midnight.AddDate(0, 0, 1).Add(offset); aligned.After(nextDay) {
+		return nextDay
+	}
+	return aligned
+}
+
+// maxConstraintPasses bounds the passes needed for a next run to satisfy all
+// the job's constraints, as satisfying one may break another
+const maxConstraintPasses = 10
 
-	durationToNextRun := s.durationToNextRun(job)
-	job.setNextRun(job.LastRun().Add(durationToNextRun))
+// applyConstraints moves nextRun to the first time satisfying the job's daily window,
+// blackout windows and excluded dates
+func (s *Scheduler) applyConstraints(job *Job, nextRun time.Time) time.Time {
+	for i := 0; i < maxConstraintPasses; i++ {
+		adjusted := s.skipExcludedDates(job, s.skipBlackoutWindows(job, s.fitDailyWindow(job, s.skipWeeksOfOtherParity(job, nextRun))))
+		if adjusted.Equal(nextRun) {
+			break
+		}
+		nextRun = adjusted
+	}
+	return nextRun
+}
+
+// fitDailyWindow moves nextRun to the start of the job's daily window if it falls outside of it
+func (s *Scheduler) fitDailyWindow(job *Job, nextRun time.Time) time.Time {
+	window := job.getDailyWindow()
+	if window == nil || window.contains(nextRun) {
+		return nextRun
+	}
+	windowStart := s.roundToMidnight(nextRun).Add(window.start)
+	if nextRun.Before(windowStart) {
+		return windowStart
+	}
+	return s.roundToMidnight(nextRun).AddDate(0, 0, 1).Add(window.start)
+}
+
+// skipWeeksOfOtherParity moves nextRun to the job's first occurrence in the
+// next week if nextRun falls in a week of the other parity
+func (s *Scheduler) skipWeeksOfOtherParity(job *Job, nextRun time.Time) time.Time {
+	weekStart, _ := s.getWeekStart()
+	// the ISO week starting on the Monday closest to the start of the week
+	isoDay := nextRun.AddDate(0, 0, daysSinceWeekStart(time.Monday, weekStart))
+	if daysSinceWeekStart(time.Monday, weekStart) > 3 {
+		isoDay = isoDay.AddDate(0, 0, -7)
+	}
+	if job.getWeekParity().matches(isoDay) {
+		return nextRun
+	}
+	midnight := s.roundToMidnight(nextRun)
+	nextWeek := midnight.AddDate(0, 0, 7-daysSinceWeekStart(midnight.Weekday(), weekStart))
+	switch job.unit {
+	case days, weeks, months:
+		// the first occurrence from the very end of the previous week
+		lastInstant := nextWeek.Add(-time.Nanosecond)
+		return lastInstant.Add(s.durationToNextRunFrom(job, lastInstant))
+	}
+	return nextWeek
+}
+
+// skipBlackoutWindows moves nextRun to the end of the job's blackout window it falls in, if any
+func (s *Scheduler) skipBlackoutWindows(job *Job, nextRun time.Time) time.Time {
+	for _, window := range job.getBlackoutWindows() {
+		if !window.blackedOut(nextRun) {
+			continue
+		}
+		midnight := s.roundToMidnight(nextRun)
+		if window.start > window.end && timeOfDay(nextRun) >= window.start {
+			// the window ends the following day
+			midnight = midnight.AddDate(0, 0, 1)
+		}
+		nextRun = midnight.Add(window.end)
+	}
+	return nextRun
+}
+
+// RecalculateNextRuns recomputes the next run of every Job from the current
+// time, e.g. after the system clock changed. The Scheduler does it
+// automatically when it detects the clock jumped while running.
+// Jobs which haven't run yet keep their first scheduled run
+func (s *Scheduler) RecalculateNextRuns() {
+	now := s.time.Now(s.Location())
+	for _, job := range s.Jobs() {
+		if job.neverRan() {
+			continue
+		}
+		job.setNextRun(s.nextRunFrom(job, now))
+	}
+}
+
+// clockJumpThreshold is by how much the wall clock may drift from the elapsed
+// time between two ticks before the Scheduler considers the clock changed
+const clockJumpThreshold = time.Minute
+
+// clockJumped returns true if the wall clock moved from lastTick to now by
+// a duration differing from the actual elapsed time by more than clockJumpThreshold
+func clockJumped(lastTick time.Time, now time.Time, elapsed time.Duration) bool {
+	// strip the monotonic clock readings to compare the wall clock
+	drift := now.Round(0).Sub(lastTick.Round(0)) - elapsed
+	return drift > clockJumpThreshold || drift < -clockJumpThreshold
 }
 
 func (s *Scheduler) durationToNextRun(job *Job) time.Duration {
-	lastRun := job.LastRun()
-	var duration time.Duration
+	return s.durationToNextRunFrom(job, job.LastRun())
+}
+
+// durationToNextRunFrom returns the duration to the job's next run as if it last ran at lastRun
+func (s *Scheduler) durationToNextRunFrom(job *Job, lastRun time.Time) time.Duration {
 	switch job.unit {
 	case seconds, minutes, hours:
-		duration = s.calculateDuration(job)
-	case days:
-		duration = s.calculateDays(job, lastRun)
-	case weeks:
-		if job.scheduledWeekday != nil { // weekday selected, Every().Monday(), for example
-			duration = s.calculateWeekday(job, lastRun)
-		} else {
-			duration = s.calculateWeeks(job, lastRun)
+		return s.calculateDuration(job, lastRun)
+	case days, weeks, months:
+		return s.calculateNearestRun(job, lastRun)
+	}
+	return 0
+}
+
+// calculateNearestRun returns the duration to the nearest of the runs the job is
+// scheduled for, at each of its times of the day and, if weekly, each of its weekdays
+func (s *Scheduler) calculateNearestRun(job *Job, lastRun time.Time) time.Duration {
+	nearest := time.Duration(math.MaxInt64)
+	consider := func(duration time.Duration) {
+		if duration < nearest {
+			nearest = duration
 		}
-	case months:
-		duration = s.calculateMonths(job, lastRun)
 	}
-	return duration
+	if job.unit == months {
+		for _, atTime := range jobAtTimes(job) {
+			consider(s.calculateMonths(job, lastRun, atTime))
+		}
+		return nearest
+	}
+	s.forEachAtTimeRun(job, func(atTime time.Duration, next func(from time.Time) time.Duration) {
+		consider(s.resolveAtTime(lastRun, atTime, next))
+	})
+	return nearest
+}
+
+// jobAtTimes returns the times of the day of the job, midnight if it has none
+func jobAtTimes(job *Job) []time.Duration {
+	if atTimes := job.getAtTimes(); len(atTimes) > 0 {
+		return atTimes
+	}
+	return []time.Duration{0}
+}
+
+// forEachAtTimeRun calls visit with each time of the day of a job scheduled
+// daily or weekly and the function computing its next run at that time from
+// a time, for each of its weekdays if it has some
+func (s *Scheduler) forEachAtTimeRun(job *Job, visit func(atTime time.Duration, next func(from time.Time) time.Duration)) {
+	weekdays := job.getScheduledWeekdays()
+	for _, atTime := range jobAtTimes(job) {
+		atTime := atTime
+		switch {
+		case job.unit == days:
+			visit(atTime, func(from time.Time) time.Duration {
+				return s.calculateDays(job, from, atTime)
+			})
+		case job.unit == weeks && len(weekdays) > 0: // weekday selected, Every().Monday(), for example
+			// the weekdays of a job running on several of them every N weeks
+			// share the weeks, not to each be N weeks apart from the last run
+			weekStart, ok := s.getWeekStart()
+			ofWeeks := job.interval > 1 && (ok || len(weekdays) > 1)
+			for _, weekday := range weekdays {
+				weekday := weekday
+				visit(atTime, func(from time.Time) time.Duration {
+					if ofWeeks {
+						return s.calculateWeekdayOfWeeks(job, from, weekStart, weekday, atTime)
+					}
+					return s.calculateWeekday(job, from, weekday, atTime)
+				})
+			}
+		case job.unit == weeks:
+			visit(atTime, func(from time.Time) time.Duration {
+				return s.calculateWeeks(job, from, atTime)
+			})
+		}
+	}
+}
+
+// skipExcludedDates advances nextRun to the job's first occurrence that doesn't fall on an excluded date
+func (s *Scheduler) skipExcludedDates(job *Job, nextRun time.Time) time.Time {
+	// each iteration moves nextRun to a later date, so it can't land
+	// on an excluded date more times than there are excluded dates
+	for i := 0; i <= job.excludedDatesCount() && job.isExcludedDate(nextRun); i++ {
+		switch job.unit {
+		case seconds, minutes, hours:
+			// jump over the rest of the day in whole intervals to keep the job's cadence
+			interval := s.calculateDuration(job, nextRun)
+			if interval <= 0 {
+				return nextRun
+			}
+			remainingDay := s.roundToMidnight(nextRun).AddDate(0, 0, 1).Sub(nextRun)
+			intervals := (remainingDay + interval - 1) / interval
+			nextRun = nextRun.Add(intervals * interval)
+		default:
+			nextRun = nextRun.Add(s.durationToNextRunFrom(job, nextRun))
+		}
+	}
+	return nextRun
 }
 
 func (s *Scheduler) getJobLastRun(job *Job) time.Time {
@@ -172,13 +603,19 @@ func (s *Scheduler) getJobLastRun(job *Job) time.Time {
 	return job.LastRun()
 }
 
-func (s *Scheduler) calculateMonths(job *Job, lastRun time.Time) time.Duration {
+func (s *Scheduler) calculateMonths(job *Job, lastRun time.Time, atTime time.Duration) time.Duration {
+	if job.skipsShortMonths || len(job.daysOfTheMonth) > 1 {
+		return s.calculateDaysOfTheMonth(job, lastRun, atTime)
+	}
+
 	lastRunRoundedMidnight := s.roundToMidnight(lastRun)
 
-	if job.dayOfTheMonth > 0 { // calculate days to j.dayOfTheMonth
-		jobDay := time.Date(lastRun.Year(), lastRun.Month(), job.dayOfTheMonth, 0, 0, 0, 0, s.Location()).Add(job.getAtTime())
-		daysDifference := int(math.Abs(lastRun.Sub(jobDay).Hours()) / 24)
-		nextRun := s.roundToMidnight(lastRun).Add(job.getAtTime())
+	if len(job.daysOfTheMonth) == 1 && job.daysOfTheMonth[0] > 0 { // calculate days to j.daysOfTheMonth
+		jobDay := time.Date(lastRun.Year(), lastRun.Month(), job.daysOfTheMonth[0], 0, 0, 0, 0, s.Location()).Add(atTime)
+		// whole days between the midnights, not to lose a day to the time of the last run
+		jobDayMidnight := s.roundToMidnight(jobDay)
+		daysDifference := int(math.Round(math.Abs(jobDayMidnight.Sub(lastRunRoundedMidnight).Hours()) / 24))
+		nextRun := s.roundToMidnight(lastRun).Add(atTime)
 		if jobDay.Before(lastRun) { // shouldn't run this month; schedule for next interval minus day difference
 			nextRun = nextRun.AddDate(0, int(job.interval), -daysDifference)
 		} else {
@@ -190,30 +627,124 @@ func (s *Scheduler) calculateMonths(job *Job, lastRun time.Time) time.Duration {
 		}
 		return s.until(lastRunRoundedMidnight, nextRun)
 	}
-	nextRun := lastRunRoundedMidnight.Add(job.getAtTime()).AddDate(0, int(job.interval), 0)
+	nextRun := lastRunRoundedMidnight.Add(atTime).AddDate(0, int(job.interval), 0)
 	return s.until(lastRunRoundedMidnight, nextRun)
 }
 
-func (s *Scheduler) calculateWeekday(job *Job, lastRun time.Time) time.Duration {
-	daysToWeekday := remainingDaysToWeekday(lastRun.Weekday(), *job.scheduledWeekday)
-	totalDaysDifference := s.calculateTotalDaysDifference(lastRun, daysToWeekday, job)
-	nextRun := s.roundToMidnight(lastRun).Add(job.getAtTime()).AddDate(0, 0, totalDaysDifference)
+// maxMonthsSearched bounds the search for a month containing one of the days of a job
+// scheduled on multiple days of the month, e.g. day 31 every 12 months starting in February
+const maxMonthsSearched = 48
+
+// calculateDaysOfTheMonth returns the duration to the nearest upcoming day of the month
+// the job is scheduled on. Days beyond the length of a month are skipped for that month
+func (s *Scheduler) calculateDaysOfTheMonth(job *Job, lastRun time.Time, atTime time.Duration) time.Duration {
+	firstOfTheMonth := time.Date(lastRun.Year(), lastRun.Month(), 1, 0, 0, 0, 0, s.Location())
+	for _, day := range job.daysOfTheMonth {
+		nextRun := firstOfTheMonth.AddDate(0, 0, day-1).Add(atTime)
+		if day <= daysInMonth(firstOfTheMonth) && nextRun.After(lastRun) {
+			return s.until(lastRun, nextRun)
+		}
+	}
+
+	for i := 1; i <= maxMonthsSearched; i++ {
+		month := firstOfTheMonth.AddDate(0, i*int(job.interval), 0)
+		for _, day := range job.daysOfTheMonth {
+			if day <= daysInMonth(month) {
+				return s.until(lastRun, month.AddDate(0, 0, day-1).Add(atTime))
+			}
+		}
+	}
+	// none of the days exist in the months the job runs
+	return time.Duration(math.MaxInt64)
+}
+
+// neverOnItsDays reports whether none of the days of the month of the job
+// ever comes in the months it runs from the month of from, the months
+// repeating every maxMonthsSearched months, leap years included
+func (s *Scheduler) neverOnItsDays(job *Job, from time.Time) bool {
+	job.RLock()
+	defer job.RUnlock()
+	if job.unit != months || !job.skipsShortMonths || job.interval <= 0 {
+		return false
+	}
+	firstOfTheMonth := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, s.Location())
+	for i := 0; i < maxMonthsSearched; i++ {
+		month := firstOfTheMonth.AddDate(0, i*int(job.interval), 0)
+		for _, day := range job.daysOfTheMonth {
+			if day <= daysInMonth(month) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func daysInMonth(firstOfTheMonth time.Time) int {
+	return firstOfTheMonth.AddDate(0, 1, -1).Day()
+}
+
+func (s *Scheduler) calculateWeekday(job *Job, lastRun time.Time, weekday time.Weekday, atTime time.Duration) time.Duration {
+	daysToWeekday := remainingDaysToWeekday(lastRun.Weekday(), weekday)
+	totalDaysDifference := s.calculateTotalDaysDifference(lastRun, daysToWeekday, job, atTime)
+	nextRun := s.roundToMidnight(lastRun).Add(atTime).AddDate(0, 0, totalDaysDifference)
+	return s.until(lastRun, nextRun)
+}
+
+// calculateWeekdayOfWeeks returns the duration to the job's next run on weekday,
+// later in the week of lastRun or in the week interval weeks after it, the
+// weeks starting on weekStart
+func (s *Scheduler) calculateWeekdayOfWeeks(job *Job, lastRun time.Time, weekStart, weekday time.Weekday, atTime time.Duration) time.Duration {
+	midnight := s.roundToMidnight(lastRun)
+	startOfWeek := midnight.AddDate(0, 0, -daysSinceWeekStart(midnight.Weekday(), weekStart))
+	offset := daysSinceWeekStart(weekday, weekStart)
+	nextRun := startOfWeek.AddDate(0, 0, offset).Add(atTime)
+	if !nextRun.After(lastRun) {
+		nextRun = startOfWeek.AddDate(0, 0, offset+7*int(job.interval)).Add(atTime)
+	}
 	return s.until(lastRun, nextRun)
 }
 
-func (s *Scheduler) calculateWeeks(job *Job, lastRun time.Time) time.Duration {
+// daysSinceWeekStart returns the number of days from weekStart to weekday
+func daysSinceWeekStart(weekday, weekStart time.Weekday) int {
+	return (int(weekday) - int(weekStart) + 7) % 7
+}
+
+// SetWeekStart sets the first day of the weeks, e.g. time.Sunday in the US.
+// Jobs running on weekdays every N weeks then run on their weekdays
+// later in the week of their last run, or else in the week N weeks after it.
+// The ISO week parity of the jobs set with ISOWeekParity is then the one of
+// the ISO week sharing the most days with the week. Unless it's set, the
+// weeks start on Monday like ISO weeks, and a single weekday every N weeks
+// is counted from the last run
+func (s *Scheduler) SetWeekStart(weekday time.Weekday) {
+	s.weekStartMutex.Lock()
+	defer s.weekStartMutex.Unlock()
+	s.weekStart = &weekday
+}
+
+// getWeekStart returns the first day of the weeks, Monday unless set
+func (s *Scheduler) getWeekStart() (time.Weekday, bool) {
+	s.weekStartMutex.RLock()
+	defer s.weekStartMutex.RUnlock()
+	if s.weekStart == nil {
+		return time.Monday, false
+	}
+	return *s.weekStart, true
+}
+
+func (s *Scheduler) calculateWeeks(job *Job, lastRun time.Time, atTime time.Duration) time.Duration {
 	totalDaysDifference := int(job.interval) * 7
-	nextRun := s.roundToMidnight(lastRun).Add(job.getAtTime()).AddDate(0, 0, totalDaysDifference)
+	nextRun := s.roundToMidnight(lastRun).Add(atTime).AddDate(0, 0, totalDaysDifference)
 	return s.until(lastRun, nextRun)
 }
 
-func (s *Scheduler) calculateTotalDaysDifference(lastRun time.Time, daysToWeekday int, job *Job) int {
+func (s *Scheduler) calculateTotalDaysDifference(lastRun time.Time, daysToWeekday int, job *Job, atTime time.Duration) int {
 	if job.interval > 1 { // every N weeks counts rest of this week and full N-1 weeks
 		return daysToWeekday + int(job.interval-1)*7
 	}
 
 	if daysToWeekday == 0 { // today, at future time or already passed
-		lastRunAtTime := time.Date(lastRun.Year(), lastRun.Month(), lastRun.Day(), 0, 0, 0, 0, s.Location()).Add(job.getAtTime())
+		lastRunAtTime := time.Date(lastRun.Year(), lastRun.Month(), lastRun.Day(), 0, 0, 0, 0, s.Location()).Add(atTime)
 		if lastRun.Before(lastRunAtTime) || lastRun.Equal(lastRunAtTime) {
 			return 0
 		}
@@ -223,15 +754,15 @@ func (s *Scheduler) calculateTotalDaysDifference(lastRun time.Time, daysToWeekda
 	return daysToWeekday
 }
 
-func (s *Scheduler) calculateDays(job *Job, lastRun time.Time) time.Duration {
+func (s *Scheduler) calculateDays(job *Job, lastRun time.Time, atTime time.Duration) time.Duration {
 	if job.interval == 1 {
-		lastRunDayPlusJobAtTime := time.Date(lastRun.Year(), lastRun.Month(), lastRun.Day(), 0, 0, 0, 0, s.Location()).Add(job.getAtTime())
+		lastRunDayPlusJobAtTime := time.Date(lastRun.Year(), lastRun.Month(), lastRun.Day(), 0, 0, 0, 0, s.Location()).Add(atTime)
 		if shouldRunToday(lastRun, lastRunDayPlusJobAtTime) {
-			return s.until(lastRun, s.roundToMidnight(lastRun).Add(job.getAtTime()))
+			return s.until(lastRun, s.roundToMidnight(lastRun).Add(atTime))
 		}
 	}
 
-	nextRunAtTime := s.roundToMidnight(lastRun).Add(job.getAtTime()).AddDate(0, 0, int(job.interval)).In(s.Location())
+	nextRunAtTime := s.roundToMidnight(lastRun).Add(atTime).AddDate(0, 0, int(job.interval)).In(s.Location())
 	return s.until(lastRun, nextRunAtTime)
 }
 
@@ -243,8 +774,7 @@ func shouldRunToday(lastRun time.Time, atTime time.Time) bool {
 	return lastRun.Before(atTime)
 }
 
-func (s *Scheduler) calculateDuration(job *Job) time.Duration {
-	lastRun := job.LastRun()
+func (s *Scheduler) calculateDuration(job *Job, lastRun time.Time) time.Duration {
 	if job.neverRan() && shouldRunAtSpecificTime(job) { // ugly. in order to avoid this we could prohibit setting .At() and allowing only .StartAt() when dealing with Duration types
 		atTime := time.Date(lastRun.Year(), lastRun.Month(), lastRun.Day(), 0, 0, 0, 0, s.Location()).Add(job.getAtTime())
 		if lastRun.Before(atTime) || lastRun.Equal(atTime) {
@@ -252,15 +782,41 @@ func (s *Scheduler) calculateDuration(job *Job) time.Duration {
 		}
 	}
 
-	interval := job.interval
-	switch job.unit {
-	case seconds:
-		return time.Duration(interval) * time.Second
-	case minutes:
-		return time.Duration(interval) * time.Minute
-	default:
-		return time.Duration(interval) * time.Hour
+	duration, err := intervalDuration(job.intervalAt(lastRun), job.unit)
+	if err != nil {
+		// rejected by Do, saturate rather than wrap to a past next run
+		return math.MaxInt64
 	}
+	if scale := s.getTimeScale(); scale != 1 {
+		scaled := float64(duration) / scale
+		if scaled >= math.MaxInt64 {
+			return math.MaxInt64
+		}
+		return time.Duration(scaled)
+	}
+	return duration
+}
+
+// SetTimeScale speeds up the Jobs running at an interval of seconds, minutes
+// or hours by dividing their interval by factor, e.g. in tests a factor of
+// 60 runs the Jobs scheduled every minute every second. The runs at specific
+// times or days aren't affected and, as the Scheduler checks for due Jobs
+// every second, intervals shorter than a second run every second. The scaled
+// intervals are measured on the clock of the Scheduler, whether real or fake.
+// A factor of 0 or less restores the real intervals
+func (s *Scheduler) SetTimeScale(factor float64) {
+	s.timeScaleMutex.Lock()
+	defer s.timeScaleMutex.Unlock()
+	s.timeScale = factor
+}
+
+func (s *Scheduler) getTimeScale() float64 {
+	s.timeScaleMutex.RLock()
+	defer s.timeScaleMutex.RUnlock()
+	if s.timeScale <= 0 {
+		return 1
+	}
+	return s.timeScale
 }
 
 func shouldRunAtSpecificTime(job *Job) bool {
@@ -303,10 +859,199 @@ func (s *Scheduler) NextRun() (*Job, time.Time) {
 	return s.Jobs()[0], s.Jobs()[0].NextRun()
 }
 
+// JobsByNextRun returns a copy of the Jobs sorted by their next run, the
+// paused Jobs and those not scheduled yet coming last. The Scheduler's
+// own order of the Jobs is left untouched
+func (s *Scheduler) JobsByNextRun() []*Job {
+	s.jobsMutex.RLock()
+	jobs := make([]*Job, len(s.jobs))
+	copy(jobs, s.jobs)
+	s.jobsMutex.RUnlock()
+
+	type entry struct {
+		job     *Job
+		nextRun time.Time
+		later   bool
+	}
+	entries := make([]entry, len(jobs))
+	for i, job := range jobs {
+		nextRun := job.NextRun()
+		entries[i] = entry{job: job, nextRun: nextRun, later: nextRun.IsZero() || job.IsPaused()}
+	}
+	sort.SliceStable(entries, func(i, k int) bool {
+		if entries[i].later != entries[k].later {
+			return !entries[i].later
+		}
+		return entries[i].nextRun.Before(entries[k].nextRun)
+	})
+	for i, e := range entries {
+		jobs[i] = e.job
+	}
+	return jobs
+}
+
+// RunCountInRange returns how many times the Job is scheduled to run from
+// start, included, to end, excluded, e.g. for capacity planning. The runs
+// follow the Job's interval, times and days, windows and excluded dates, and
+// the Job is left untouched. Jobs not scheduled at specific times or days
+// start their runs at start. Jitter, NextRunFunc, custom strategies and run
+// limits are ignored
+func (s *Scheduler) RunCountInRange(job *Job, start, end time.Time) int {
+	if !end.After(start) {
+		return 0
+	}
+	// the runs at a fixed interval are counted without stepping through them
+	if d, ok := s.fixedInterval(job, start); ok {
+		return int((end.Sub(start) + d - 1) / d)
+	}
+	count := 0
+	s.forEachRunInRange(job, start, end, func(time.Time) { count++ })
+	return count
+}
+
+// maxRunsSearched bounds the runs stepped through by NextRunAfter to reach
+// the given time from the Job's next run
+const maxRunsSearched = 100000
+
+// fixedInterval returns the interval between all the runs of the job from
+// start, if they are at an interval of seconds, minutes or hours which no
+// window, excluded date or week parity moves
+func (s *Scheduler) fixedInterval(job *Job, start time.Time) (time.Duration, bool) {
+	switch job.unit {
+	case seconds, minutes, hours:
+	default:
+		return 0, false
+	}
+	job.RLock()
+	constrained := job.weekdayIntervals != nil || job.dailyWindow != nil || len(job.blackoutWindows) > 0 ||
+		len(job.excludedDates) > 0 || job.weekParity != anyWeek
+	job.RUnlock()
+	if constrained || (job.neverRan() && shouldRunAtSpecificTime(job)) {
+		return 0, false
+	}
+	d := s.durationToNextRunFrom(job, start)
+	return d, d > 0 && d < math.MaxInt64
+}
+
+// forecastRun returns the run of the job following from, constrained like
+// its scheduled runs, leaving the job untouched
+func (s *Scheduler) forecastRun(job *Job, from time.Time) time.Time {
+	// the months on a single day spilling over are counted from the midnight starting the day
+	if job.unit == months && !job.skipsShortMonths && len(job.daysOfTheMonth) <= 1 {
+		return s.applyConstraints(job, s.roundToMidnight(from).Add(s.durationToNextRunFrom(job, from)))
+	}
+	return s.applyConstraints(job, from.Add(s.durationToNextRunFrom(job, from)))
+}
+
+// NextRunAfter returns the first run of the Job at or after t, e.g. for a
+// calendar view, following its interval, times and days, windows and
+// excluded dates. The runs at an interval are in phase with the Job's next
+// run, or start at t if the Job isn't scheduled yet. The Job is left
+// untouched. Jitter, NextRunFunc and custom strategies are ignored
+func (s *Scheduler) NextRunAfter(job *Job, t time.Time) time.Time {
+	ref := job.NextRun()
+	switch job.unit {
+	case seconds, minutes, hours:
+		d, err := intervalDuration(job.intervalAt(ref), job.unit)
+		if ref.IsZero() || err != nil {
+			return s.applyConstraints(job, t)
+		}
+		// step from the next run by whole intervals to the first run not before t
+		if ref.Before(t) {
+			n := (t.Sub(ref) + d - 1) / d
+			return s.applyConstraints(job, ref.Add(n*d))
+		}
+		return s.applyConstraints(job, ref.Add(-(ref.Sub(t)/d)*d))
+	}
+
+	var anchorOffset time.Duration
+	if job.isAnchored() {
+		anchorOffset = time.Nanosecond
+	}
+	next := func(from time.Time) time.Time { return s.forecastRun(job, from) }
+	if ref.IsZero() || !ref.Before(t) {
+		return next(t.Add(-anchorOffset))
+	}
+	// step through the runs from the next run to keep in phase with it
+	run := ref
+	for i := 0; i < maxRunsSearched && run.Before(t); i++ {
+		nextRun := next(run.Add(anchorOffset))
+		if !nextRun.After(run) {
+			break
+		}
+		run = nextRun
+	}
+	return run
+}
+
+// forEachRunInRange calls visit with each run of the Job from start,
+// included, to end, excluded, as computed by RunCountInRange
+func (s *Scheduler) forEachRunInRange(job *Job, start, end time.Time, visit func(run time.Time)) {
+	next := func(from time.Time) time.Time { return s.forecastRun(job, from) }
+	run := s.applyConstraints(job, start)
+	// the runs at specific times or days are computed from just before the
+	// first one and just after the others, as computing them from the exact
+	// time of a run yields that same run
+	var anchorOffset time.Duration
+	switch job.unit {
+	case days, weeks, months:
+		if job.isAnchored() {
+			anchorOffset = time.Nanosecond
+			run = next(start.Add(-anchorOffset))
+		}
+	}
+	for !run.Before(start) && run.Before(end) {
+		visit(run)
+		nextRun := next(run.Add(anchorOffset))
+		if !nextRun.After(run) {
+			break
+		}
+		run = nextRun
+	}
+}
+
+// Backfill replays the Job for each of its runs from start, included, to
+// end, excluded, as computed by RunCountInRange. The runs are called in
+// order in the calling goroutine, with the time of the run as the time.Time
+// param of the Job's function, which is required, else ErrNoRunTimeParam is
+// returned. The state and the schedule of the Job are left untouched. The
+// backfill stops at the first run returning an error, which is returned
+func (s *Scheduler) Backfill(job *Job, start, end time.Time) error {
+	job.RLock()
+	jobFunc, params := job.funcs[job.jobFunc], job.fparams[job.jobFunc]
+	job.RUnlock()
+	if jobFunc == nil {
+		return ErrNoFuncSet
+	}
+	if !takesRunTime(jobFunc, params) {
+		return ErrNoRunTimeParam
+	}
+	var err error
+	s.forEachRunInRange(job, start, end, func(run time.Time) {
+		if err != nil {
+			return
+		}
+		opts := s.runOptions()
+		opts.now = func() time.Time { return run }
+		if runErr := job.callFuncs(opts); runErr != nil {
+			err = fmt.Errorf("backfill run at %s: %w", run.Format(time.RFC3339), runErr)
+		}
+	})
+	return err
+}
+
 // Every schedules a new periodic Job with interval
 func (s *Scheduler) Every(interval uint64) *Scheduler {
+	jobs := s.Jobs()
+	// the previous Job is done with once the next one is scheduled, report
+	// it right away if Do was never called on it
+	if len(jobs) > 0 {
+		if previous := jobs[len(jobs)-1]; !previous.hasFunc() && previous.Err() == nil {
+			previous.setErr(previous.funcErr())
+		}
+	}
 	job := NewJob(interval)
-	s.setJobs(append(s.Jobs(), job))
+	s.setJobs(append(jobs, job))
 	return s
 }
 
@@ -318,7 +1063,24 @@ func (s *Scheduler) RunPending() {
 }
 
 func (s *Scheduler) runAndReschedule(job *Job) error {
-	if err := s.run(job); err != nil {
+	if job.coalescesMissed() {
+		s.coalesceMissedRuns(job)
+	}
+	var afterRun func()
+	if s.getOverrunPolicy() == ExtendOverrun {
+		// schedule the next run from the completion of this one
+		afterRun = func() { s.scheduleNextRun(job) }
+	} else if job.hasErrorSchedule() {
+		// schedule the next run once the error of this one is known if it
+		// failed, or if it follows a failed run, rescheduled at the error interval
+		failedBefore := job.getLastRunFailed()
+		afterRun = func() {
+			if failedBefore || job.getLastRunFailed() {
+				s.scheduleNextRun(job)
+			}
+		}
+	}
+	if err := s.runThen(job, afterRun); err != nil {
 		return err
 	}
 	s.scheduleNextRun(job)
@@ -326,24 +1088,247 @@ func (s *Scheduler) runAndReschedule(job *Job) error {
 }
 
 func (s *Scheduler) run(job *Job) error {
-	job.setLastRun(s.time.Now(s.Location()))
-	go job.run()
+	return s.runThen(job, nil)
+}
+
+// coalesceMissedRuns counts the runs the job missed since its next run,
+// which the coming run catches up with
+func (s *Scheduler) coalesceMissedRuns(job *Job) {
+	now := s.time.Now(s.Location())
+	scheduledAt := job.NextRun()
+	if scheduledAt.IsZero() || !scheduledAt.Before(now) {
+		return
+	}
+	if missed := s.RunCountInRange(job, scheduledAt, now.Add(time.Nanosecond)) - 1; missed > 0 {
+		job.addCoalescedRuns(missed)
+	}
+}
+
+// runThen runs the job like run, and calls afterRun, if not nil, once
+// the run completed
+func (s *Scheduler) runThen(job *Job, afterRun func()) error {
+	now := s.time.Now(s.Location())
+	scheduledAt := job.NextRun()
+	// runs triggered before the job is due, e.g. by RunAll, aren't late
+	if !scheduledAt.IsZero() && !now.Before(scheduledAt) {
+		job.setLastLatency(now.Sub(scheduledAt))
+	} else {
+		scheduledAt = now
+	}
+	job.setLastRun(now)
+	// the trigger takes its place in OrderedMode as it arrives, not once
+	// its goroutine runs, and waits for its turn before taking any slot
+	opts := s.runOptions()
+	opts.ticket = job.takeOrderedTicket(opts.overrun)
+	run := s.wrapRun(job, opts)
+	if limiter := s.getConcurrencyLimiter(); limiter != nil {
+		limitedRun := run
+		run = func() {
+			limiter.acquire(scheduledAt)
+			defer limiter.release()
+			limitedRun()
+		}
+	}
+	// wait for the pool before taking a global slot, not to hold it meanwhile
+	if pool := job.getResourcePool(); pool != nil {
+		limiter := s.poolLimiter(pool)
+		pooledRun := run
+		run = func() {
+			limiter.acquire(scheduledAt)
+			defer limiter.release()
+			pooledRun()
+		}
+	}
+	if afterRun != nil {
+		completedRun := run
+		run = func() {
+			completedRun()
+			afterRun()
+		}
+	}
+	if ticket := opts.ticket; ticket != nil {
+		orderedRun := run
+		run = func() {
+			// released also if a middleware doesn't call the run
+			defer ticket.release()
+			ticket.wait()
+			orderedRun()
+		}
+	}
+	if job.isSync() {
+		run()
+		return nil
+	}
+	s.runningJobs.Add(1)
+	tracked := func() {
+		defer s.runningJobs.Done()
+		run()
+	}
+	if workers := s.getWorkerPool(); workers != nil && workers.submit(tracked) {
+		return nil
+	}
+	go tracked()
 	return nil
 }
 
+// SetMaxConcurrentJobs limits to n the number of jobs running at the same time.
+// Jobs triggered while the limit is reached wait for a running job to return,
+// the job which was scheduled the earliest running first. A limit of 0 or less
+// removes the limit. Sync jobs waiting for a slot block the scheduler
+func (s *Scheduler) SetMaxConcurrentJobs(n int) {
+	s.limiterMutex.Lock()
+	defer s.limiterMutex.Unlock()
+	if n <= 0 {
+		s.limiter = nil
+		return
+	}
+	s.limiter = newConcurrencyLimiter(n)
+}
+
+func (s *Scheduler) getConcurrencyLimiter() *concurrencyLimiter {
+	s.limiterMutex.RLock()
+	defer s.limiterMutex.RUnlock()
+	return s.limiter
+}
+
+// Use adds middlewares wrapping the execution of every job. Middlewares
+// are nested in the order they are added, the first one being the outermost
+func (s *Scheduler) Use(middlewares ...Middleware) {
+	s.middlewaresMutex.Lock()
+	defer s.middlewaresMutex.Unlock()
+	s.middlewares = append(s.middlewares, middlewares...)
+}
+
+// wrapRun returns the run of the job with opts wrapped by the middlewares
+func (s *Scheduler) wrapRun(job *Job, opts runOptions) func() {
+	s.middlewaresMutex.RLock()
+	defer s.middlewaresMutex.RUnlock()
+	run := func() {
+		start := s.time.Now(s.Location())
+		atomic.AddInt64(&s.stats.running, 1)
+		err := job.runWith(opts)
+		atomic.AddInt64(&s.stats.running, -1)
+		if err != ErrRunSkipped {
+			atomic.AddInt64(&s.stats.runs, 1)
+			if err != nil {
+				atomic.AddInt64(&s.stats.errors, 1)
+			}
+			s.emitResult(RunResult{Job: job, Start: start, Duration: s.time.Now(s.Location()).Sub(start), Err: err})
+		}
+		s.signalRun()
+		if err != nil && err != ErrRunSkipped && job.isCritical() {
+			s.criticalFailure(job, err)
+		}
+		if err == nil {
+			s.runTriggeredJobs(job)
+		}
+	}
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		middleware, next := s.middlewares[i], run
+		run = func() {
+			middleware(job, next)
+		}
+	}
+	return run
+}
+
+// runTriggeredJobs runs the jobs of the Scheduler set to run after each
+// successful run of job with TriggerOnCompletionOf
+func (s *Scheduler) runTriggeredJobs(job *Job) {
+	for _, triggered := range job.getTriggers() {
+		scheduled := false
+		s.ForEach(func(j *Job) bool {
+			scheduled = j == triggered
+			return !scheduled
+		})
+		switch {
+		case !scheduled:
+		case triggered.IsPaused():
+			triggered.skip(SkipReasonPaused)
+		default:
+			_ = s.run(triggered)
+		}
+	}
+}
+
+// SetDefaultTimeout sets the timeout of the runs of the Jobs without their
+// own, set with Job.SetTimeout, as a safety net against hanging jobs.
+// A timeout of 0 or less removes the default timeout
+func (s *Scheduler) SetDefaultTimeout(d time.Duration) {
+	s.timeoutMutex.Lock()
+	defer s.timeoutMutex.Unlock()
+	s.defaultTimeout = d
+}
+
+// runOptions returns the settings of the Scheduler applying to the runs of the jobs
+func (s *Scheduler) runOptions() runOptions {
+	s.timeoutMutex.RLock()
+	defaultTimeout := s.defaultTimeout
+	s.timeoutMutex.RUnlock()
+	return runOptions{
+		defaultTimeout: defaultTimeout,
+		tracer:         s.getTracer(),
+		overrun:        s.getOverrunPolicy(),
+		now:            func() time.Time { return s.time.Now(s.Location()) },
+	}
+}
+
+// OnCriticalFailure sets a function called with the critical Job which failed
+// and its error, before the Scheduler stops
+func (s *Scheduler) OnCriticalFailure(f func(job *Job, err error)) {
+	s.criticalMutex.Lock()
+	defer s.criticalMutex.Unlock()
+	s.onCriticalFailure = f
+}
+
+// criticalFailure stops the scheduler after the failure of a critical job
+func (s *Scheduler) criticalFailure(job *Job, err error) {
+	s.criticalMutex.RLock()
+	onCriticalFailure := s.onCriticalFailure
+	s.criticalMutex.RUnlock()
+	if onCriticalFailure != nil {
+		onCriticalFailure(job, err)
+	}
+	// the job may run within the scheduler loop, which must be free to receive the stop signal
+	go s.Stop()
+}
+
+// MaxLatency returns the highest latency of the Jobs' last runs. A growing
+// value indicates that the Scheduler can't keep up with its Jobs
+func (s *Scheduler) MaxLatency() time.Duration {
+	var maxLatency time.Duration
+	for _, job := range s.Jobs() {
+		if latency := job.LastLatency(); latency > maxLatency {
+			maxLatency = latency
+		}
+	}
+	return maxLatency
+}
+
 // RunAll run all Jobs regardless if they are scheduled to run or not
 func (s *Scheduler) RunAll() {
-	s.RunAllWithDelay(0)
+	s.RunAllWithDelayDuration(0)
 }
 
-// RunAllWithDelay runs all Jobs with delay seconds
+// RunAllWithDelay runs all Jobs with delay seconds.
+//
+// Deprecated: use RunAllWithDelayDuration, which takes the delay as a time.Duration
 func (s *Scheduler) RunAllWithDelay(d int) {
+	s.RunAllWithDelayDuration(time.Duration(d) * time.Second)
+}
+
+// RunAllWithDelayDuration runs all Jobs regardless if they are scheduled to run or not,
+// waiting d after triggering each of them to stagger the runs. The runs are
+// still subject to SingletonMode and SetMaxConcurrentJobs
+func (s *Scheduler) RunAllWithDelayDuration(d time.Duration) {
 	for _, job := range s.Jobs() {
 		err := s.run(job)
 		if err != nil {
 			continue
 		}
-		s.time.Sleep(time.Duration(d) * time.Second)
+		if d > 0 {
+			s.time.Sleep(d)
+		}
 	}
 }
 
@@ -361,6 +1346,15 @@ func (s *Scheduler) RemoveByReference(j *Job) {
 	})
 }
 
+// ResetJob resets the Job with Job.Reset and, if the scheduler is running,
+// schedules its next run as if it was just added
+func (s *Scheduler) ResetJob(j *Job) {
+	j.Reset()
+	if s.IsRunning() {
+		s.scheduleNextRun(j)
+	}
+}
+
 func (s *Scheduler) removeByCondition(shouldRemove func(*Job) bool) {
 	retainedJobs := make([]*Job, 0)
 	for _, job := range s.Jobs() {
@@ -382,6 +1376,64 @@ func (s *Scheduler) RemoveJobByTag(tag string) error {
 	return nil
 }
 
+// FindJobsByLabel returns the Jobs having the label key set to value
+func (s *Scheduler) FindJobsByLabel(key, value string) []*Job {
+	var jobs []*Job
+	for _, job := range s.Jobs() {
+		if job.hasLabel(key, value) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// FindJobByName returns the first Job named name with Job.SetName.
+// Unnamed Jobs can't be found by name
+func (s *Scheduler) FindJobByName(name string) (*Job, bool) {
+	if name == "" {
+		return nil, false
+	}
+	for _, job := range s.Jobs() {
+		if job.Name() == name {
+			return job, true
+		}
+	}
+	return nil, false
+}
+
+// FindJobsByFunc returns the Jobs running the function named fnName,
+// e.g. "main.backup", as returned by Job.FuncName
+func (s *Scheduler) FindJobsByFunc(fnName string) []*Job {
+	var jobs []*Job
+	for _, job := range s.Jobs() {
+		if job.FuncName() == fnName {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// AllTags returns the sorted tags of all the Jobs, without duplicates
+func (s *Scheduler) AllTags() []string {
+	// snapshot the jobs as the scheduler may be sorting them
+	s.jobsMutex.RLock()
+	jobs := append([]*Job(nil), s.jobs...)
+	s.jobsMutex.RUnlock()
+
+	unique := make(map[string]struct{})
+	for _, job := range jobs {
+		for _, tag := range job.Tags() {
+			unique[tag] = struct{}{}
+		}
+	}
+	tags := make([]string, 0, len(unique))
+	for tag := range unique {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
 // Find first job index by given string
 func (s *Scheduler) findJobsIndexByTag(tag string) (int, error) {
 	for i, job := range s.Jobs() {
@@ -416,17 +1468,78 @@ func (s *Scheduler) Clear() {
 }
 
 // Stop stops the scheduler. This is a no-op if the scheduler is already stopped .
-func (s *Scheduler) Stop() {
-	if s.IsRunning() {
-		s.stopScheduler()
+// It returns the state of the jobs, which can be restored with Resume
+func (s *Scheduler) Stop() SchedulerState {
+	if s.stop() {
+		s.notifyLifecycle(s.getOnStop())
+	}
+	return s.state()
+}
+
+// GracefulStop stops the scheduler and waits for the running jobs to return.
+// It returns the state of the jobs, which can be restored with Resume
+func (s *Scheduler) GracefulStop() SchedulerState {
+	stopped := s.stop()
+	s.runningJobs.Wait()
+	if stopped {
+		s.notifyLifecycle(s.getOnStop())
+	}
+	return s.state()
+}
+
+// stop stops the scheduler if it's running and returns true if it was.
+// Of concurrent calls, e.g. several critical failures, only one stops it
+func (s *Scheduler) stop() bool {
+	if !s.swapRunning(false) {
+		return false
 	}
+	s.stopScheduler()
+	return true
+}
+
+// OnStart sets a function called each time the Scheduler starts
+func (s *Scheduler) OnStart(f func()) {
+	s.lifecycleMutex.Lock()
+	defer s.lifecycleMutex.Unlock()
+	s.onStart = f
+}
+
+// OnStop sets a function called each time the Scheduler stops. With
+// GracefulStop, it's called once the running jobs returned
+func (s *Scheduler) OnStop(f func()) {
+	s.lifecycleMutex.Lock()
+	defer s.lifecycleMutex.Unlock()
+	s.onStop = f
+}
+
+func (s *Scheduler) getOnStart() func() {
+	s.lifecycleMutex.RLock()
+	defer s.lifecycleMutex.RUnlock()
+	return s.onStart
 }
 
+func (s *Scheduler) getOnStop() func() {
+	s.lifecycleMutex.RLock()
+	defer s.lifecycleMutex.RUnlock()
+	return s.onStop
+}
+
+func (s *Scheduler) notifyLifecycle(f func()) {
+	if f != nil {
+		f()
+	}
+}
+
+// stopScheduler signals the scheduling goroutine, stopped once it received it
 func (s *Scheduler) stopScheduler() {
 	s.stopChan <- struct{}{}
 }
 
-// Do specifies the jobFunc that should be called every time the Job runs
+// Do specifies the jobFunc that should be called every time the Job runs.
+// A jobFunc taking a context.Context as first param, not given in params,
+// receives the context of the run, cancelled by Job.AbortCurrentRun. A
+// time.Time param following it, or first, not given in params, receives the
+// time of the run
 func (s *Scheduler) Do(jobFun interface{}, params ...interface{}) (*Job, error) {
 	j := s.getCurrentJob()
 	if j.err != nil {
@@ -436,11 +1549,27 @@ func (s *Scheduler) Do(jobFun interface{}, params ...interface{}) (*Job, error)
 		return nil, j.err
 	}
 
-	typ := reflect.TypeOf(jobFun)
-	if typ.Kind() != reflect.Func {
+	if j.interval == 0 {
+		if !j.isAnchored() {
+			// a zero interval would reschedule the job without any
+			// delay and keep the scheduler busy running it
+			s.RemoveByReference(j)
+			return nil, ErrZeroInterval
+		}
+		// anchored jobs run once per unit at their specific time or day
+		j.interval = 1
+	}
+
+	if _, err := intervalDuration(j.interval, j.unit); err != nil {
+		j.setErr(err)
+		s.RemoveByReference(j)
+		return nil, err
+	}
+
+	if err := validateJobFunc(jobFun, params); err != nil {
 		// delete the job for the same reason as above
 		s.RemoveByReference(j)
-		return nil, ErrNotAFunction
+		return nil, err
 	}
 
 	fname := getFunctionName(jobFun)
@@ -456,17 +1585,154 @@ func (s *Scheduler) Do(jobFun interface{}, params ...interface{}) (*Job, error)
 	return j, nil
 }
 
-// At schedules the Job at a specific time of day in the form "HH:MM:SS" or "HH:MM"
-func (s *Scheduler) At(t string) *Scheduler {
+// DoMethod specifies the method of the receiver, named methodName, to be
+// called each time the Job runs, e.g. for config-driven dispatch. It returns
+// ErrMethodNotFound if the receiver is nil or has no such exported method
+func (s *Scheduler) DoMethod(receiver interface{}, methodName string, params ...interface{}) (*Job, error) {
+	var method reflect.Value
+	value := reflect.ValueOf(receiver)
+	if value.IsValid() && !(value.Kind() == reflect.Ptr && value.IsNil()) {
+		method = value.MethodByName(methodName)
+	}
+	if !method.IsValid() {
+		j := s.getCurrentJob()
+		j.setErr(ErrMethodNotFound)
+		s.RemoveByReference(j)
+		return nil, ErrMethodNotFound
+	}
+	return s.Do(method.Interface(), params...)
+}
+
+// Schedule adds the job like Do, but returns the Scheduler to chain the
+// scheduling of other jobs, e.g.
+//
+//	s.Every(1).Minute().Schedule(f).Every(2).Hours().Schedule(g)
+//
+// The job added by the last call is returned by LastJob, while the first
+// error of the chain is returned by Err
+func (s *Scheduler) Schedule(jobFun interface{}, params ...interface{}) *Scheduler {
+	job, err := s.Do(jobFun, params...)
+	s.scheduleMutex.Lock()
+	defer s.scheduleMutex.Unlock()
+	s.lastJob = job
+	if err != nil && s.scheduleErr == nil {
+		s.scheduleErr = err
+	}
+	return s
+}
+
+// AfterFunc runs the job function once after the delay d, after which
+// the Job is removed from the Scheduler
+func (s *Scheduler) AfterFunc(d time.Duration, jobFun interface{}, params ...interface{}) (*Job, error) {
+	s.Every(1).Second().StartAt(s.time.Now(s.Location()).Add(d))
+	job := s.getCurrentJob()
+	job.LimitRunsTo(1)
+	job.RemoveAfterLastRun()
+	return s.Do(jobFun, params...)
+}
+
+// LastJob returns the job added by the last call to Schedule, nil if it failed
+func (s *Scheduler) LastJob() *Job {
+	s.scheduleMutex.RLock()
+	defer s.scheduleMutex.RUnlock()
+	return s.lastJob
+}
+
+// Err returns the first error which occurred while adding jobs with Schedule
+func (s *Scheduler) Err() error {
+	s.scheduleMutex.RLock()
+	defer s.scheduleMutex.RUnlock()
+	return s.scheduleErr
+}
+
+// Validate checks that every Job can be scheduled, e.g. before starting the
+// Scheduler to fail fast, and returns an error for each Job which can't,
+// wrapping the reason: an error of its configuration, ErrNoFuncSet,
+// ErrPeriodNotSpecified, ErrZeroInterval, ErrIntervalTooLarge or
+// ErrNeverScheduled if none of its days ever comes, e.g. the 31st in the
+// Februaries only
+func (s *Scheduler) Validate() []error {
+	var errs []error
+	for i, job := range s.Jobs() {
+		if err := s.validateJob(job); err != nil {
+			name := job.Name()
+			if name == "" {
+				name = job.FuncName()
+			}
+			errs = append(errs, fmt.Errorf("job %d (%s): %w", i, name, err))
+		}
+	}
+	return errs
+}
+
+// validateJob returns the reason why the job can't be scheduled, if any
+func (s *Scheduler) validateJob(job *Job) error {
+	// the error of a job which ran is the one of its last run, not of its configuration
+	if err := job.Err(); err != nil && job.neverRan() {
+		return err
+	}
+	if err := job.funcErr(); err != nil {
+		return err
+	}
+	job.RLock()
+	interval, unit := job.interval, job.unit
+	job.RUnlock()
+	if unit == 0 {
+		return ErrPeriodNotSpecified
+	}
+	if interval == 0 && !job.isAnchored() {
+		return ErrZeroInterval
+	}
+	if _, err := intervalDuration(interval, unit); err != nil {
+		return err
+	}
+	if job.getNextRunFunc() != nil {
+		return nil
+	}
+	now := s.time.Now(s.Location())
+	if s.neverOnItsDays(job, now) || s.durationToNextRunFrom(job, now) == math.MaxInt64 {
+		return ErrNeverScheduled
+	}
+	return nil
+}
+
+// At schedules the Job at specific times of day in the form "HH:MM:SS" or "HH:MM".
+// A Job scheduled at several times runs at each of them
+func (s *Scheduler) At(t string, others ...string) *Scheduler {
 	j := s.getCurrentJob()
-	hour, min, sec, err := parseTime(t)
-	if err != nil {
-		j.err = ErrTimeFormat
-		return s
+	atTimes := make([]time.Duration, 0, len(others)+1)
+	for _, t := range append([]string{t}, others...) {
+		atTime, err := ParseAtTime(t)
+		if err != nil {
+			j.err = ErrTimeFormat
+			return s
+		}
+		// save atTime start as duration from midnight
+		atTimes = append(atTimes, atTime)
 	}
-	// save atTime start as duration from midnight
-	j.setAtTime(time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute + time.Duration(sec)*time.Second)
-	j.startsImmediately = false
+	sort.Slice(atTimes, func(i, k int) bool { return atTimes[i] < atTimes[k] })
+	j.setAtTimes(removeDuplicateAtTimes(atTimes))
+	j.setStartsImmediately(false)
+	s.rescheduleIfStarted(j)
+	return s
+}
+
+// removeDuplicateAtTimes removes the duplicates from sorted times
+func removeDuplicateAtTimes(sortedTimes []time.Duration) []time.Duration {
+	times := sortedTimes[:1]
+	for _, t := range sortedTimes[1:] {
+		if t != times[len(times)-1] {
+			times = append(times, t)
+		}
+	}
+	return times
+}
+
+// Jitter delays each scheduled run of the Job by a random duration
+// of up to max, spreading the load of jobs sharing the same schedule
+func (s *Scheduler) Jitter(max time.Duration) *Scheduler {
+	job := s.getCurrentJob()
+	job.setJitter(max)
 	return s
 }
 
@@ -487,19 +1753,58 @@ func (s *Scheduler) StartAt(t time.Time) *Scheduler {
 
 // shouldRun returns true if the Job should be run now
 func (s *Scheduler) shouldRun(j *Job) bool {
+	// skip the jobs whose function was never set with Do, or is missing
+	if err := j.funcErr(); err != nil {
+		j.setErr(err)
+		return false
+	}
+	now := s.time.Now(s.Location())
+	// remove the job once its lifetime has passed
+	if j.expired(now) {
+		s.RemoveByReference(j)
+		return false
+	}
+	shouldRun := j.shouldRun() && now.Unix() >= j.NextRun().Unix()
+	// hold the trigger until the run in progress completes and reschedules the job
+	if shouldRun && s.getOverrunPolicy() == ExtendOverrun && j.isRunning() {
+		return false
+	}
+	if shouldRun && j.IsPaused() {
+		// hold the due run for the catch-up run after Resume
+		if j.coalescesMissed() {
+			return false
+		}
+		j.skip(SkipReasonPaused)
+		j.setNextRun(s.nextRunFrom(j, now))
+		return false
+	}
 
 	// option remove the job's in the scheduler after its last execution
-	if j.getRemoveAfterLastRun() && (j.getMaxRuns()-j.RunCount()) == 1 {
+	if shouldRun && j.getRemoveAfterLastRun() && (j.MaxRuns()-j.RunCount()) == 1 {
 		s.RemoveByReference(j)
 	}
 
-	return j.shouldRun() && s.time.Now(s.Location()).Unix() >= j.NextRun().Unix()
+	return shouldRun
 }
 
-// setUnit sets the unit type
+// setUnit sets the unit type and reschedules the Job if it's started,
+// e.g. once the setters of its days set them
 func (s *Scheduler) setUnit(unit timeUnit) {
 	currentJob := s.getCurrentJob()
+	currentJob.Lock()
 	currentJob.unit = unit
+	currentJob.Unlock()
+	s.rescheduleIfStarted(currentJob)
+}
+
+// rescheduleIfStarted recomputes the next run of a Job whose schedule
+// changed after it was scheduled, so that the new schedule takes effect
+// from its last run rather than after its next run
+func (s *Scheduler) rescheduleIfStarted(job *Job) {
+	if !s.IsRunning() || !job.hasFunc() || job.NextRun().IsZero() {
+		return
+	}
+	job.setNextRun(s.nextRunFrom(job, s.getJobLastRun(job)))
 }
 
 // Second sets the unit with seconds
@@ -567,8 +1872,11 @@ func (s *Scheduler) Month(dayOfTheMonth int) *Scheduler {
 // Months sets the unit with months
 func (s *Scheduler) Months(dayOfTheMonth int) *Scheduler {
 	job := s.getCurrentJob()
-	job.dayOfTheMonth = dayOfTheMonth
+	job.Lock()
+	job.daysOfTheMonth = []int{dayOfTheMonth}
+	job.skipsShortMonths = false
 	job.startsImmediately = false
+	job.Unlock()
 	s.setUnit(months)
 	return s
 }
@@ -578,15 +1886,67 @@ func (s *Scheduler) Months(dayOfTheMonth int) *Scheduler {
 // spill over to the next month. Similarly, if it's less than 0,
 // it will go back to the month before
 
-// Weekday sets the start with a specific weekday weekday
+// DaysOfMonth sets the unit with months and schedules the Job on each of the
+// given days of the month. Unlike Months, days beyond the length of a month
+// are skipped for that month
+func (s *Scheduler) DaysOfMonth(days ...int) *Scheduler {
+	job := s.getCurrentJob()
+	daysOfTheMonth := make([]int, 0, len(days))
+	for _, day := range days {
+		if day < 1 || day > 31 {
+			job.setErr(ErrInvalidDayOfMonth)
+			return s
+		}
+		daysOfTheMonth = append(daysOfTheMonth, day)
+	}
+	if len(daysOfTheMonth) == 0 {
+		job.setErr(ErrInvalidDayOfMonth)
+		return s
+	}
+	sort.Ints(daysOfTheMonth)
+	job.Lock()
+	job.daysOfTheMonth = removeDuplicateDays(daysOfTheMonth)
+	job.skipsShortMonths = true
+	job.startsImmediately = false
+	job.Unlock()
+	s.setUnit(months)
+	return s
+}
+
+func removeDuplicateDays(sortedDays []int) []int {
+	days := sortedDays[:1]
+	for _, day := range sortedDays[1:] {
+		if day != days[len(days)-1] {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// Weekday sets the start with a specific weekday weekday. It can be chained
+// to schedule the Job on several weekdays, e.g. Monday().Thursday()
 func (s *Scheduler) Weekday(startDay time.Weekday) *Scheduler {
 	job := s.getCurrentJob()
-	job.scheduledWeekday = &startDay
+	job.Lock()
+	job.scheduledWeekdays = addWeekday(job.scheduledWeekdays, startDay)
 	job.startsImmediately = false
+	job.Unlock()
 	s.setUnit(weeks)
 	return s
 }
 
+// addWeekday inserts weekday in the sorted weekdays, unless it's already there
+func addWeekday(weekdays []time.Weekday, weekday time.Weekday) []time.Weekday {
+	i := sort.Search(len(weekdays), func(i int) bool { return weekdays[i] >= weekday })
+	if i < len(weekdays) && weekdays[i] == weekday {
+		return weekdays
+	}
+	weekdays = append(weekdays, 0)
+	copy(weekdays[i+1:], weekdays[i:])
+	weekdays[i] = weekday
+	return weekdays
+}
+
 // Monday sets the start day as Monday
 func (s *Scheduler) Monday() *Scheduler {
 	return s.Weekday(time.Monday)
@@ -627,7 +1987,40 @@ func (s *Scheduler) getCurrentJob() *Job {
 }
 
 func (s *Scheduler) scheduleAllJobs() {
+	now := s.time.Now(s.Location())
+	stagger, staggered := s.getStartupStagger(), 0
 	for _, j := range s.Jobs() {
+		if err := j.funcErr(); err != nil {
+			j.setErr(err)
+			continue
+		}
+		// keep the run missed while stopped due, to catch up with it
+		if j.coalescesMissed() && !j.neverRan() && !j.NextRun().IsZero() && j.NextRun().Before(now) {
+			continue
+		}
+		immediate := j.neverRan() && j.NextRun().IsZero() && j.getStartsImmediately()
 		s.scheduleNextRun(j)
+		// spread the first runs of the jobs starting immediately
+		if immediate && j.neverRan() && stagger > 0 {
+			j.setNextRun(j.NextRun().Add(time.Duration(staggered) * stagger))
+			j.setNextRunExplanation(nextRunExplanation{source: "starts immediately, staggered"})
+			staggered++
+		}
 	}
 }
+
+// SetStartupStagger spreads the first runs of the jobs starting immediately
+// when the scheduler starts, each of them running d after the previous one
+// instead of all at once. Their next runs are scheduled from their first one
+// as usual. A duration of 0 or less runs them all at start
+func (s *Scheduler) SetStartupStagger(d time.Duration) {
+	s.staggerMutex.Lock()
+	defer s.staggerMutex.Unlock()
+	s.startupStagger = d
+}
+
+func (s *Scheduler) getStartupStagger() time.Duration {
+	s.staggerMutex.RLock()
+	defer s.staggerMutex.RUnlock()
+	return s.startupStagger
+}
@@ -1,8 +1,13 @@
 package gocron
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -55,6 +60,31 @@ func TestExecutionSecond(t *testing.T) {
 	assert.Equal(t, true, success, "Task did not get called")
 }
 
+func TestRunAllWithDelayDuration(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	var mu sync.Mutex
+	var starts []time.Time
+	for i := 0; i < 3; i++ {
+		_, err := s.Every(1).Hour().Do(func() {
+			mu.Lock()
+			defer mu.Unlock()
+			starts = append(starts, time.Now())
+		})
+		require.NoError(t, err)
+	}
+
+	delay := 100 * time.Millisecond
+	s.RunAllWithDelayDuration(delay)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, starts, 3)
+	for i := 1; i < len(starts); i++ {
+		gap := starts[i].Sub(starts[i-1])
+		assert.True(t, gap >= delay-10*time.Millisecond && gap < 2*delay, "the runs should be %s apart, got %s", delay, gap)
+	}
+}
+
 func TestExecutionSeconds(t *testing.T) {
 	sched := NewScheduler(time.UTC)
 	jobDone := make(chan bool)
@@ -266,6 +296,61 @@ func TestRemoveByTag(t *testing.T) {
 	assert.Equal(t, 1, scheduler.Len(), "Incorrect number of jobs after removing non-existent job")
 }
 
+func TestFindJobsByLabel(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	prod1, _ := s.Every(1).Minute().Do(task)
+	prod1.SetLabel("env", "prod")
+	dev, _ := s.Every(1).Minute().Do(task)
+	dev.SetLabel("env", "dev")
+	prod2, _ := s.Every(1).Minute().Do(task)
+	prod2.SetLabel("env", "prod")
+	_, _ = s.Every(1).Minute().Do(task)
+
+	assert.Equal(t, []*Job{prod1, prod2}, s.FindJobsByLabel("env", "prod"))
+	assert.Equal(t, []*Job{dev}, s.FindJobsByLabel("env", "dev"))
+	assert.Empty(t, s.FindJobsByLabel("env", "staging"))
+	assert.Empty(t, s.FindJobsByLabel("team", "prod"))
+}
+
+func TestFindJobByName(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	backup, _ := s.Every(1).Minute().Do(task)
+	backup.SetName("backup")
+	_, _ = s.Every(1).Minute().Do(task)
+
+	job, ok := s.FindJobByName("backup")
+	assert.True(t, ok)
+	assert.Equal(t, backup, job)
+	_, ok = s.FindJobByName("cleanup")
+	assert.False(t, ok)
+	_, ok = s.FindJobByName("")
+	assert.False(t, ok)
+}
+
+func TestFindJobsByFunc(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	first, _ := s.Every(1).Minute().Do(task)
+	_, _ = s.Every(1).Minute().Do(taskWithParams, 1, "hello")
+	second, _ := s.Every(1).Hour().Do(task)
+
+	assert.Equal(t, "github.com/go-co-op/gocron.task", first.FuncName())
+	assert.Equal(t, []*Job{first, second}, s.FindJobsByFunc(first.FuncName()))
+	assert.Empty(t, s.FindJobsByFunc("main.unknown"))
+}
+
+func TestAllTags(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	assert.Empty(t, s.AllTags())
+
+	j1, _ := s.Every(1).Minute().Do(task)
+	j1.Tag("db", "reports")
+	j2, _ := s.Every(1).Minute().Do(task)
+	j2.Tag("reports", "cache", "db")
+	_, _ = s.Every(1).Minute().Do(task)
+
+	assert.Equal(t, []string{"cache", "db", "reports"}, s.AllTags())
+}
+
 func TestJobs(t *testing.T) {
 	s := NewScheduler(time.UTC)
 	s.Every(1).Minute().Do(task)
@@ -382,6 +467,82 @@ func TestScheduler_Stop(t *testing.T) {
 	})
 }
 
+func TestScheduler_IsRunning(t *testing.T) {
+	t.Run("running between start and stop", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		assert.False(t, s.IsRunning())
+		s.StartAsync()
+		assert.True(t, s.IsRunning())
+		s.Stop()
+		assert.False(t, s.IsRunning())
+	})
+
+	t.Run("starting a running scheduler is a no-op", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		job, err := s.Every(1).Hour().Do(task)
+		require.NoError(t, err)
+
+		stopChan := s.StartAsync()
+		nextRun := job.NextRun()
+		assert.Equal(t, stopChan, s.StartAsync())
+		assert.Equal(t, nextRun, job.NextRun(), "jobs should not be rescheduled")
+		assert.True(t, s.IsRunning())
+
+		s.Stop()
+		assert.False(t, s.IsRunning())
+	})
+
+	t.Run("restarting a stopped scheduler", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		s.StartAsync()
+		s.Stop()
+		s.StartAsync()
+		assert.True(t, s.IsRunning())
+		s.Stop()
+		assert.False(t, s.IsRunning())
+	})
+
+	t.Run("the previous scheduling goroutine leaves a restarted scheduler running", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		for i := 0; i < 20; i++ {
+			s.StartAsync()
+			s.Stop()
+			s.StartAsync()
+			time.Sleep(5 * time.Millisecond)
+			assert.True(t, s.IsRunning())
+			assert.False(t, s.StartedAt().IsZero())
+			s.Stop()
+		}
+	})
+}
+
+func TestScheduler_Uptime(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+	assert.True(t, s.StartedAt().IsZero())
+	assert.Zero(t, s.Uptime())
+
+	s.setRunning(true)
+	assert.Equal(t, start, s.StartedAt())
+	now = now.Add(90 * time.Second)
+	assert.Equal(t, 90*time.Second, s.Uptime())
+	s.setRunning(false)
+	assert.True(t, s.StartedAt().IsZero())
+	assert.Zero(t, s.Uptime())
+
+	t.Run("uptime grows after start", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		s.StartAsync()
+		defer s.Stop()
+		uptime := s.Uptime()
+		time.Sleep(20 * time.Millisecond)
+		assert.True(t, s.Uptime() >= uptime+20*time.Millisecond)
+		assert.Zero(t, NewScheduler(time.UTC).Uptime(), "a fresh scheduler has no uptime")
+	})
+}
+
 func TestScheduler_StartAt(t *testing.T) {
 	scheduler := NewScheduler(time.Local)
 	now := time.Now()
@@ -400,6 +561,58 @@ func TestScheduler_StartAt(t *testing.T) {
 	assert.Equal(t, now.Second(), nextRun.Second())
 }
 
+func TestScheduler_ChangeScheduleAfterStart(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+	s.setRunning(true)
+	defer s.setRunning(false)
+
+	job, err := s.Every(1).Second().Do(func() {})
+	require.NoError(t, err)
+	job.setLastRun(now)
+	s.scheduleNextRun(job)
+	assert.Equal(t, now.Add(time.Second), job.NextRun())
+
+	s.Minutes()
+	assert.Equal(t, now.Add(time.Minute), job.NextRun())
+
+	now = job.NextRun()
+	job.setLastRun(now)
+	s.scheduleNextRun(job)
+	assert.Equal(t, now.Add(time.Minute), job.NextRun())
+
+	s.Day().At("12:00")
+	assert.Equal(t, time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC), job.NextRun())
+	assert.NoError(t, job.Err())
+
+	// January 1st 2020 is a Wednesday
+	s.Friday()
+	assert.Equal(t, time.Date(2020, time.January, 3, 12, 0, 0, 0, time.UTC), job.NextRun())
+	s.DaysOfMonth(15)
+	assert.Equal(t, time.Date(2020, time.January, 15, 12, 0, 0, 0, time.UTC), job.NextRun())
+
+	t.Run("interval", func(t *testing.T) {
+		now = time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+		job, err := s.Every(1).Second().Do(func() {})
+		require.NoError(t, err)
+		job.setLastRun(now)
+		s.scheduleNextRun(job)
+		require.NoError(t, s.run(job))
+		s.runningJobs.Wait()
+
+		job.TimesPer(4, "hours")
+		for i := 0; i < 2; i++ {
+			now = job.NextRun()
+			job.setLastRun(now)
+			s.scheduleNextRun(job)
+			assert.Equal(t, now.Add(15*time.Minute), job.NextRun(), "the new interval should take effect")
+		}
+		assert.Equal(t, 1, job.RunCount())
+		assert.NoError(t, job.Err())
+	})
+}
+
 func TestScheduler_CalculateNextRun(t *testing.T) {
 	day := time.Hour * 24
 	januaryFirst2020At := func(hour, minute, second int) time.Time {
@@ -486,7 +699,7 @@ func TestScheduler_CalculateNextRun(t *testing.T) {
 			job: Job{
 				interval: 1,
 				unit:     days,
-				atTime:   _getHours(9) + _getMinutes(30),
+				atTimes:  []time.Duration{_getHours(9) + _getMinutes(30)},
 				lastRun:  januaryFirst2020At(0, 0, 0),
 			},
 			wantTimeUntilNextRun: _getHours(9) + _getMinutes(30),
@@ -496,7 +709,7 @@ func TestScheduler_CalculateNextRun(t *testing.T) {
 			job: Job{
 				interval: 1,
 				unit:     days,
-				atTime:   _getHours(9) + _getMinutes(30),
+				atTimes:  []time.Duration{_getHours(9) + _getMinutes(30)},
 				lastRun:  januaryFirst2020At(9, 30, 0),
 			},
 			wantTimeUntilNextRun: 1 * day,
@@ -515,7 +728,7 @@ func TestScheduler_CalculateNextRun(t *testing.T) {
 			job: Job{
 				interval: 1,
 				unit:     days,
-				atTime:   8*time.Hour + 30*time.Minute,
+				atTimes:  []time.Duration{8*time.Hour + 30*time.Minute},
 				lastRun:  januaryFirst2020At(8, 30, 0),
 			},
 			wantTimeUntilNextRun: 24 * time.Hour,
@@ -525,7 +738,7 @@ func TestScheduler_CalculateNextRun(t *testing.T) {
 			job: Job{
 				interval: 1,
 				unit:     days,
-				atTime:   8*time.Hour + 30*time.Minute,
+				atTimes:  []time.Duration{8*time.Hour + 30*time.Minute},
 				lastRun:  januaryFirst2020At(5, 30, 0),
 			},
 			wantTimeUntilNextRun: 3 * time.Hour,
@@ -535,7 +748,7 @@ func TestScheduler_CalculateNextRun(t *testing.T) {
 			job: Job{
 				interval: 2,
 				unit:     days,
-				atTime:   8*time.Hour + 30*time.Minute,
+				atTimes:  []time.Duration{8*time.Hour + 30*time.Minute},
 				lastRun:  januaryFirst2020At(5, 30, 0),
 			},
 			wantTimeUntilNextRun: (2 * day) + 3*time.Hour,
@@ -545,7 +758,7 @@ func TestScheduler_CalculateNextRun(t *testing.T) {
 			job: Job{
 				interval: 2,
 				unit:     days,
-				atTime:   8*time.Hour + 30*time.Minute,
+				atTimes:  []time.Duration{8*time.Hour + 30*time.Minute},
 				lastRun:  januaryFirst2020At(8, 30, 0),
 			},
 			wantTimeUntilNextRun: 2 * day,
@@ -564,7 +777,7 @@ func TestScheduler_CalculateNextRun(t *testing.T) {
 			name: "every week with .At time rule should run respect .At time rule",
 			job: Job{
 				interval: 1,
-				atTime:   _getHours(9) + _getMinutes(30),
+				atTimes:  []time.Duration{_getHours(9) + _getMinutes(30)},
 				unit:     weeks,
 				lastRun:  januaryFirst2020At(9, 30, 0),
 			},
@@ -630,7 +843,7 @@ func TestScheduler_CalculateNextRun(t *testing.T) {
 			job: Job{
 				interval: 1,
 				unit:     months,
-				atTime:   _getHours(9) + _getMinutes(30),
+				atTimes:  []time.Duration{_getHours(9) + _getMinutes(30)},
 				lastRun:  januaryFirst2020At(9, 30, 0),
 			},
 			wantTimeUntilNextRun: 31*day + _getHours(9) + _getMinutes(30),
@@ -638,10 +851,10 @@ func TestScheduler_CalculateNextRun(t *testing.T) {
 		{
 			name: "every month at day should consider at days",
 			job: Job{
-				interval:      1,
-				unit:          months,
-				dayOfTheMonth: 2,
-				lastRun:       januaryFirst2020At(0, 0, 0),
+				interval:       1,
+				unit:           months,
+				daysOfTheMonth: []int{2},
+				lastRun:        januaryFirst2020At(0, 0, 0),
 			},
 			wantTimeUntilNextRun: 1 * day,
 		},
@@ -650,7 +863,7 @@ func TestScheduler_CalculateNextRun(t *testing.T) {
 			job: Job{
 				interval: 1,
 				unit:     months,
-				atTime:   _getHours(9) + _getMinutes(30),
+				atTimes:  []time.Duration{_getHours(9) + _getMinutes(30)},
 				lastRun:  januaryFirst2020At(0, 0, 0),
 			},
 			wantTimeUntilNextRun: 31*day + _getHours(9) + _getMinutes(30),
@@ -658,113 +871,164 @@ func TestScheduler_CalculateNextRun(t *testing.T) {
 		{
 			name: "every month on the first day, but started on january 8th, should run February 1st",
 			job: Job{
-				interval:      1,
-				unit:          months,
-				dayOfTheMonth: 1,
-				lastRun:       januaryFirst2020At(0, 0, 0).AddDate(0, 0, 7),
+				interval:       1,
+				unit:           months,
+				daysOfTheMonth: []int{1},
+				lastRun:        januaryFirst2020At(0, 0, 0).AddDate(0, 0, 7),
 			},
 			wantTimeUntilNextRun: 24 * day,
 		},
 		{
 			name: "every 2 months at day 1, starting at day 1, should run in 2 months",
 			job: Job{
-				interval:      2,
-				unit:          months,
-				dayOfTheMonth: 1,
-				lastRun:       januaryFirst2020At(0, 0, 0),
+				interval:       2,
+				unit:           months,
+				daysOfTheMonth: []int{1},
+				lastRun:        januaryFirst2020At(0, 0, 0),
 			},
 			wantTimeUntilNextRun: 31*day + 29*day, // 2020 january and february
 		},
 		{
 			name: "every 2 months at day 2, starting at day 1, should run in 2 months + 1 day",
 			job: Job{
-				interval:      2,
-				unit:          months,
-				dayOfTheMonth: 2,
-				lastRun:       januaryFirst2020At(0, 0, 0),
+				interval:       2,
+				unit:           months,
+				daysOfTheMonth: []int{2},
+				lastRun:        januaryFirst2020At(0, 0, 0),
 			},
 			wantTimeUntilNextRun: 31*day + 29*day + 1*day, // 2020 january and february
 		},
 		{
 			name: "every 2 months at day 1, starting at day 2, should run in 2 months - 1 day",
 			job: Job{
-				interval:      2,
-				unit:          months,
-				dayOfTheMonth: 1,
-				lastRun:       januaryFirst2020At(0, 0, 0).AddDate(0, 0, 1),
+				interval:       2,
+				unit:           months,
+				daysOfTheMonth: []int{1},
+				lastRun:        januaryFirst2020At(0, 0, 0).AddDate(0, 0, 1),
 			},
 			wantTimeUntilNextRun: 30*day + 29*day, // 2020 january and february
 		},
 		{
 			name: "every 13 months at day 1, starting at day 2 run in 13 months - 1 day",
 			job: Job{
-				interval:      13,
-				unit:          months,
-				dayOfTheMonth: 1,
-				lastRun:       januaryFirst2020At(0, 0, 0).AddDate(0, 0, 1),
+				interval:       13,
+				unit:           months,
+				daysOfTheMonth: []int{1},
+				lastRun:        januaryFirst2020At(0, 0, 0).AddDate(0, 0, 1),
 			},
 			wantTimeUntilNextRun: januaryFirst2020At(0, 0, 0).AddDate(0, 13, -1).Sub(januaryFirst2020At(0, 0, 0)),
 		},
+		{
+			name: "every month on days 1 and 15, just ran on day 1, should run on day 15",
+			job: Job{
+				interval:       1,
+				unit:           months,
+				daysOfTheMonth: []int{1, 15},
+				lastRun:        januaryFirst2020At(0, 0, 0),
+			},
+			wantTimeUntilNextRun: 14 * day,
+		},
+		{
+			name: "every month on days 1 and 15, just ran on day 15, should run on the 1st of next month",
+			job: Job{
+				interval:       1,
+				unit:           months,
+				daysOfTheMonth: []int{1, 15},
+				lastRun:        januaryFirst2020At(0, 0, 0).AddDate(0, 0, 14),
+			},
+			wantTimeUntilNextRun: 17 * day,
+		},
+		{
+			name: "every month on days 1 and 15 at time, started on day 20, should run on the 1st of next month at time",
+			job: Job{
+				interval:       1,
+				unit:           months,
+				daysOfTheMonth: []int{1, 15},
+				atTimes:        []time.Duration{_getHours(9) + _getMinutes(30)},
+				lastRun:        januaryFirst2020At(0, 0, 0).AddDate(0, 0, 19),
+			},
+			wantTimeUntilNextRun: 12*day + _getHours(9) + _getMinutes(30),
+		},
+		{
+			name: "every month on days 15 and 31, started on february 16th, should skip february 31st",
+			job: Job{
+				interval:       1,
+				unit:           months,
+				daysOfTheMonth: []int{15, 31},
+				lastRun:        time.Date(2020, time.February, 16, 0, 0, 0, 0, time.UTC),
+			},
+			wantTimeUntilNextRun: 28 * day,
+		},
+		{
+			name: "every 12 months on days 30 and 31, started in february, should never run",
+			job: Job{
+				interval:       12,
+				unit:           months,
+				daysOfTheMonth: []int{30, 31},
+				lastRun:        time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC),
+			},
+			wantTimeUntilNextRun: time.Duration(math.MaxInt64),
+		},
 		//// WEEKDAYS
 		{
 			name: "every weekday starting on one day before it should run this weekday",
 			job: Job{
-				interval:         1,
-				unit:             weeks,
-				scheduledWeekday: _tuesdayWeekday(),
-				lastRun:          mondayAt(0, 0, 0),
+				interval:          1,
+				unit:              weeks,
+				scheduledWeekdays: _tuesdayWeekday(),
+				lastRun:           mondayAt(0, 0, 0),
 			},
 			wantTimeUntilNextRun: 1 * day,
 		},
 		{
 			name: "every weekday starting on same weekday should run on same immediately",
 			job: Job{
-				interval:         1,
-				unit:             weeks,
-				scheduledWeekday: _tuesdayWeekday(),
-				lastRun:          mondayAt(0, 0, 0).AddDate(0, 0, 1),
+				interval:          1,
+				unit:              weeks,
+				scheduledWeekdays: _tuesdayWeekday(),
+				lastRun:           mondayAt(0, 0, 0).AddDate(0, 0, 1),
 			},
 			wantTimeUntilNextRun: 0,
 		},
 		{
 			name: "every 2 weekdays counting this week's weekday should run next weekday",
 			job: Job{
-				interval:         2,
-				unit:             weeks,
-				scheduledWeekday: _tuesdayWeekday(),
-				lastRun:          mondayAt(0, 0, 0),
+				interval:          2,
+				unit:              weeks,
+				scheduledWeekdays: _tuesdayWeekday(),
+				lastRun:           mondayAt(0, 0, 0),
 			},
 			wantTimeUntilNextRun: 8 * day,
 		},
 		{
 			name: "every weekday starting on one day after should count days remaning",
 			job: Job{
-				interval:         1,
-				unit:             weeks,
-				scheduledWeekday: _tuesdayWeekday(),
-				lastRun:          mondayAt(0, 0, 0).AddDate(0, 0, 2),
+				interval:          1,
+				unit:              weeks,
+				scheduledWeekdays: _tuesdayWeekday(),
+				lastRun:           mondayAt(0, 0, 0).AddDate(0, 0, 2),
 			},
 			wantTimeUntilNextRun: 6 * day,
 		},
 		{
 			name: "every weekday starting before jobs .At() time should run at same day at time",
 			job: Job{
-				interval:         1,
-				unit:             weeks,
-				atTime:           _getHours(9) + _getMinutes(30),
-				scheduledWeekday: _tuesdayWeekday(),
-				lastRun:          mondayAt(0, 0, 0).AddDate(0, 0, 1),
+				interval:          1,
+				unit:              weeks,
+				atTimes:           []time.Duration{_getHours(9) + _getMinutes(30)},
+				scheduledWeekdays: _tuesdayWeekday(),
+				lastRun:           mondayAt(0, 0, 0).AddDate(0, 0, 1),
 			},
 			wantTimeUntilNextRun: _getHours(9) + _getMinutes(30),
 		},
 		{
 			name: "every weekday starting at same day at time that already passed should run at next week at time",
 			job: Job{
-				interval:         1,
-				unit:             weeks,
-				atTime:           _getHours(9) + _getMinutes(30),
-				scheduledWeekday: _tuesdayWeekday(),
-				lastRun:          mondayAt(10, 30, 0).AddDate(0, 0, 1),
+				interval:          1,
+				unit:              weeks,
+				atTimes:           []time.Duration{_getHours(9) + _getMinutes(30)},
+				scheduledWeekdays: _tuesdayWeekday(),
+				lastRun:           mondayAt(10, 30, 0).AddDate(0, 0, 1),
 			},
 			wantTimeUntilNextRun: 6*day + _getHours(23) + _getMinutes(0),
 		},
@@ -780,9 +1044,8 @@ func TestScheduler_CalculateNextRun(t *testing.T) {
 }
 
 // helper test method
-func _tuesdayWeekday() *time.Weekday {
-	tuesday := time.Tuesday
-	return &tuesday
+func _tuesdayWeekday() []time.Weekday {
+	return []time.Weekday{time.Tuesday}
 }
 
 // helper test method
@@ -800,6 +1063,49 @@ func _getMinutes(i int) time.Duration {
 	return time.Duration(i) * time.Minute
 }
 
+func TestScheduler_DaysOfMonth(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		days         []int
+		expectedDays []int
+		expectedErr  error
+	}{
+		{"single day", []int{15}, []int{15}, nil},
+		{"sorted and deduplicated", []int{15, 1, 15, 31}, []int{1, 15, 31}, nil},
+		{"no days", []int{}, nil, ErrInvalidDayOfMonth},
+		{"day zero", []int{0, 15}, nil, ErrInvalidDayOfMonth},
+		{"day out of range", []int{1, 32}, nil, ErrInvalidDayOfMonth},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := NewScheduler(time.UTC)
+			job, err := s.Every(1).DaysOfMonth(tc.days...).Do(task)
+			if tc.expectedErr != nil {
+				assert.Equal(t, tc.expectedErr, err)
+				assert.Zero(t, s.Len())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedDays, job.ScheduledDaysOfMonth())
+		})
+	}
+}
+
+func TestScheduler_DaysOfMonthSkipsShortMonths(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	job, err := s.Every(1).DaysOfMonth(31).Do(task)
+	require.NoError(t, err)
+	nextRun := func(lastRun time.Time) time.Time {
+		job.setLastRun(lastRun)
+		return lastRun.Add(s.durationToNextRun(job))
+	}
+	assert.Equal(t, time.Date(2021, time.March, 31, 0, 0, 0, 0, time.UTC), nextRun(time.Date(2021, time.February, 1, 0, 0, 0, 0, time.UTC)),
+		"February should be skipped")
+	assert.Equal(t, time.Date(2021, time.May, 31, 0, 0, 0, 0, time.UTC), nextRun(time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC)),
+		"a 30-day month should be skipped")
+}
+
 func TestScheduler_Do(t *testing.T) {
 	t.Run("adding a new job before scheduler starts does not schedule job", func(t *testing.T) {
 		s := NewScheduler(time.UTC)
@@ -870,6 +1176,36 @@ func TestDo(t *testing.T) {
 				assert.Equal(t, 1, len(s.jobs))
 			},
 		},
+		{
+			name: "error due to the number of params not matching the function",
+			evalFunc: func(s *Scheduler) {
+				_, err := s.Every(1).Second().Do(taskWithParams, 1)
+				assert.Equal(t, ErrParamsNotAdapted, err)
+				_, err = s.Every(1).Second().Do(task, 1)
+				assert.Equal(t, ErrParamsNotAdapted, err)
+				assert.Zero(t, len(s.jobs), "The job should be deleted if the params don't match the function")
+			},
+		},
+		{
+			name: "error due to the type of params not matching the function",
+			evalFunc: func(s *Scheduler) {
+				_, err := s.Every(1).Second().Do(taskWithParams, "hello", 1)
+				assert.Equal(t, ErrParamTypeMismatch, err)
+				_, err = s.Every(1).Second().Do(func(i int) {}, nil)
+				assert.Equal(t, ErrParamTypeMismatch, err)
+				assert.Zero(t, len(s.jobs), "The job should be deleted if the params don't match the function")
+			},
+		},
+		{
+			name: "params assignable to the function's params",
+			evalFunc: func(s *Scheduler) {
+				_, err := s.Every(1).Second().Do(func(v interface{}, err error, p *int) {}, 1, errors.New("error"), nil)
+				assert.NoError(t, err)
+				assert.Equal(t, 1, len(s.jobs))
+				s.jobs[0].run()
+				assert.NoError(t, s.jobs[0].Err())
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -883,7 +1219,7 @@ func TestRemoveAfterExec(t *testing.T) {
 	s := NewScheduler(time.UTC)
 	s.StartAsync()
 
-	job, err := s.Every(1).StartAt(time.Now().Add(1*time.Second)).Do(task, s)
+	job, err := s.Every(1).StartAt(time.Now().Add(1*time.Second)).Do(task)
 	require.NoError(t, err)
 
 	job.LimitRunsTo(1)
@@ -893,3 +1229,1416 @@ func TestRemoveAfterExec(t *testing.T) {
 
 	assert.Zero(t, len(s.Jobs()))
 }
+
+func TestScheduler_IntervalTooLarge(t *testing.T) {
+	maxSeconds := uint64(math.MaxInt64 / int64(time.Second))
+
+	t.Run("interval overflowing a duration is rejected", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		job, err := s.Every(maxSeconds + 1).Seconds().Do(task)
+		assert.Equal(t, ErrIntervalTooLarge, err)
+		assert.Nil(t, job)
+		assert.Zero(t, s.Len(), "The job should be deleted if its interval is too large")
+	})
+
+	t.Run("interval at the boundary is allowed", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		now := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+		job, err := s.Every(maxSeconds).Seconds().Do(task)
+		require.NoError(t, err)
+		job.setLastRun(now)
+		s.scheduleNextRun(job)
+		assert.True(t, job.NextRun().After(now), "the next run should not wrap to the past")
+	})
+
+	t.Run("calendar units are checked too", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		_, err := s.Every(math.MaxUint32).Days().Do(task)
+		assert.Equal(t, ErrIntervalTooLarge, err)
+	})
+}
+
+func TestScheduler_ZeroInterval(t *testing.T) {
+	t.Run("zero interval job is rejected", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		job, err := s.Every(0).Seconds().Do(task)
+		assert.Equal(t, ErrZeroInterval, err)
+		assert.Nil(t, job)
+		assert.Zero(t, s.Len(), "The job should be deleted if its interval is zero")
+	})
+
+	t.Run("zero interval job anchored at a time is allowed", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		now := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+		job, err := s.Every(0).Day().At("10:30").Do(task)
+		require.NoError(t, err)
+		s.scheduleNextRun(job)
+		assert.Equal(t, time.Date(2020, time.January, 2, 10, 30, 0, 0, time.UTC), job.NextRun(), "the job should not be rescheduled without delay")
+	})
+}
+
+func TestScheduler_Jitter(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	jitterSequence := func(seed int64) []time.Duration {
+		s := NewScheduler(time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+		s.SetRandSource(rand.NewSource(seed))
+		job, err := s.Every(1).Minute().Jitter(30 * time.Second).Do(task)
+		require.NoError(t, err)
+		job.setStartsImmediately(false)
+
+		var sequence []time.Duration
+		for i := 0; i < 5; i++ {
+			s.scheduleNextRun(job)
+			jitter := job.NextRun().Sub(now) - time.Minute
+			require.True(t, jitter >= 0 && jitter < 30*time.Second, "jitter %s is out of range", jitter)
+			sequence = append(sequence, jitter)
+		}
+		return sequence
+	}
+
+	assert.Equal(t, jitterSequence(42), jitterSequence(42), "the same seed should produce the same jitter")
+	assert.NotEqual(t, jitterSequence(42), jitterSequence(7))
+}
+
+func TestScheduler_SyncAndAsyncJobs(t *testing.T) {
+	newSlowJob := func(s *Scheduler, mu *sync.Mutex, events *[]string, name string) *Job {
+		job, err := s.Every(1).Second().Do(func() {
+			mu.Lock()
+			*events = append(*events, name+" start")
+			mu.Unlock()
+			time.Sleep(100 * time.Millisecond)
+			mu.Lock()
+			*events = append(*events, name+" end")
+			mu.Unlock()
+		})
+		require.NoError(t, err)
+		return job
+	}
+
+	t.Run("sync jobs run in order within the scheduler", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		var mu sync.Mutex
+		var events []string
+		newSlowJob(s, &mu, &events, "first").Sync()
+		newSlowJob(s, &mu, &events, "second").Sync()
+
+		s.RunAll()
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, []string{"first start", "first end", "second start", "second end"}, events)
+	})
+
+	t.Run("async jobs don't block the scheduler and are drained on graceful stop", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		var mu sync.Mutex
+		var events []string
+		newSlowJob(s, &mu, &events, "first").Async()
+		newSlowJob(s, &mu, &events, "second")
+
+		s.RunAll()
+		mu.Lock()
+		assert.NotContains(t, events, "first end", "RunAll should not wait for async jobs")
+		mu.Unlock()
+
+		s.GracefulStop()
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Contains(t, events, "first end")
+		assert.Contains(t, events, "second end")
+	})
+}
+
+func TestScheduler_Use(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	var events []string
+	job, err := s.Every(1).Second().Do(func() {
+		events = append(events, "run")
+	})
+	require.NoError(t, err)
+	job.Sync()
+
+	record := func(name string) Middleware {
+		return func(j *Job, next func()) {
+			assert.Equal(t, job, j)
+			events = append(events, name+" before")
+			next()
+			events = append(events, name+" after")
+		}
+	}
+	s.Use(record("outer"))
+	s.Use(record("inner"))
+
+	s.RunAll()
+	assert.Equal(t, []string{"outer before", "inner before", "run", "inner after", "outer after"}, events)
+}
+
+func TestScheduler_ExcludeDates(t *testing.T) {
+	januaryAt := func(day, hour, minute int) time.Time {
+		return time.Date(2020, time.January, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		desc          string
+		schedule      func(s *Scheduler) *Scheduler
+		excludedDates []time.Time
+		now           []time.Time
+		expected      []time.Time
+	}{
+		{
+			desc:          "daily job skips the excluded date and resumes the day after",
+			schedule:      func(s *Scheduler) *Scheduler { return s.Every(1).Day().At("09:00") },
+			excludedDates: []time.Time{time.Date(2020, time.January, 2, 15, 0, 0, 0, time.FixedZone("UTC-8", -8*60*60))},
+			now:           []time.Time{januaryAt(1, 10, 0), januaryAt(3, 9, 0)},
+			expected:      []time.Time{januaryAt(3, 9, 0), januaryAt(4, 9, 0)},
+		},
+		{
+			desc:          "daily job skips consecutive excluded dates",
+			schedule:      func(s *Scheduler) *Scheduler { return s.Every(1).Day().At("09:00") },
+			excludedDates: []time.Time{januaryAt(3, 0, 0), januaryAt(2, 0, 0)},
+			now:           []time.Time{januaryAt(1, 10, 0)},
+			expected:      []time.Time{januaryAt(4, 9, 0)},
+		},
+		{
+			desc:          "interval job skips the excluded date keeping its cadence",
+			schedule:      func(s *Scheduler) *Scheduler { return s.Every(45).Minutes() },
+			excludedDates: []time.Time{januaryAt(2, 0, 0)},
+			now:           []time.Time{januaryAt(1, 23, 0), januaryAt(1, 23, 0), januaryAt(1, 23, 45)},
+			expected:      []time.Time{januaryAt(1, 23, 0), januaryAt(1, 23, 45), januaryAt(3, 0, 30)},
+		},
+		{
+			desc:          "job starting immediately doesn't start on an excluded date",
+			schedule:      func(s *Scheduler) *Scheduler { return s.Every(1).Hour() },
+			excludedDates: []time.Time{januaryAt(1, 0, 0)},
+			now:           []time.Time{januaryAt(1, 12, 0)},
+			expected:      []time.Time{januaryAt(2, 0, 0)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := NewScheduler(time.UTC)
+			var now time.Time
+			s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+			job, err := tc.schedule(s).Do(task)
+			require.NoError(t, err)
+			job.ExcludeDates(tc.excludedDates...)
+
+			for i := range tc.now {
+				now = tc.now[i]
+				if i > 0 {
+					job.setLastRun(now) // the job ran at its scheduled time
+				}
+				s.scheduleNextRun(job)
+				assert.Equal(t, tc.expected[i], job.NextRun())
+			}
+		})
+	}
+}
+
+func TestScheduler_Latency(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	scheduledAt := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	now := scheduledAt
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	onTime, err := s.Every(1).Minute().StartAt(scheduledAt).Do(task)
+	require.NoError(t, err)
+	onTime.Sync()
+	late, err := s.Every(1).Minute().StartAt(scheduledAt.Add(-3 * time.Second)).Do(task)
+	require.NoError(t, err)
+	late.Sync()
+	notDue, err := s.Every(1).Minute().StartAt(scheduledAt.Add(time.Hour)).Do(task)
+	require.NoError(t, err)
+	notDue.Sync()
+
+	s.RunPending()
+	assert.Zero(t, onTime.LastLatency())
+	assert.Equal(t, 3*time.Second, late.LastLatency())
+	assert.Equal(t, 3*time.Second, s.MaxLatency())
+
+	require.NoError(t, s.run(notDue))
+	assert.Zero(t, notDue.LastLatency(), "runs triggered before the job is due aren't late")
+	assert.Equal(t, 3*time.Second, s.MaxLatency())
+}
+
+func TestScheduler_RecalculateNextRuns(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	now := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	minuteJob, err := s.Every(1).Minute().Do(task)
+	require.NoError(t, err)
+	dailyJob, err := s.Every(1).Day().At("12:00").Do(task)
+	require.NoError(t, err)
+	startAt := now.Add(24 * time.Hour)
+	startAtJob, err := s.Every(1).Minute().StartAt(startAt).Do(task)
+	require.NoError(t, err)
+
+	s.scheduleAllJobs()
+	minuteJob.setLastRun(now)
+	s.scheduleNextRun(minuteJob)
+	require.Equal(t, now.Add(time.Minute), minuteJob.NextRun())
+
+	// the clock jumps 5 hours ahead
+	now = now.Add(5 * time.Hour)
+	s.RecalculateNextRuns()
+
+	assert.Equal(t, now.Add(time.Minute), minuteJob.NextRun(), "missed runs should not fire immediately")
+	assert.Equal(t, time.Date(2020, time.January, 2, 12, 0, 0, 0, time.UTC), dailyJob.NextRun())
+	assert.Equal(t, startAt, startAtJob.NextRun(), "jobs which haven't run yet keep their first run")
+	assert.Equal(t, now.Add(-5*time.Hour), minuteJob.LastRun(), "recalculating should not change the last run")
+}
+
+func TestClockJumped(t *testing.T) {
+	lastTick := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		desc     string
+		now      time.Time
+		elapsed  time.Duration
+		expected bool
+	}{
+		{"regular tick", lastTick.Add(time.Second), time.Second, false},
+		{"slow tick", lastTick.Add(10 * time.Second), 10 * time.Second, false},
+		{"jump ahead", lastTick.Add(3 * time.Hour), time.Second, true},
+		{"jump behind", lastTick.Add(-time.Hour), time.Second, true},
+		{"small correction", lastTick.Add(30 * time.Second), time.Second, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, clockJumped(lastTick, tc.now, tc.elapsed))
+		})
+	}
+}
+
+func TestScheduler_Between(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	now := time.Date(2020, time.January, 1, 16, 40, 0, 0, time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	job, err := s.Every(10).Minutes().Do(task)
+	require.NoError(t, err)
+	job.Between("09:00", "17:00")
+
+	var runs []time.Time
+	s.scheduleNextRun(job)
+	for i := 0; i < 5; i++ {
+		now = job.NextRun()
+		runs = append(runs, now)
+		job.setLastRun(now)
+		s.scheduleNextRun(job)
+	}
+
+	assert.Equal(t, []time.Time{
+		time.Date(2020, time.January, 1, 16, 40, 0, 0, time.UTC),
+		time.Date(2020, time.January, 1, 16, 50, 0, 0, time.UTC),
+		time.Date(2020, time.January, 1, 17, 0, 0, 0, time.UTC),
+		time.Date(2020, time.January, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2020, time.January, 2, 9, 10, 0, 0, time.UTC),
+	}, runs)
+
+	t.Run("a job starting outside of its window waits for the window", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		now := time.Date(2020, time.January, 1, 7, 0, 0, 0, time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+		job, err := s.Every(10).Minutes().Do(task)
+		require.NoError(t, err)
+		job.Between("09:00", "17:00")
+		s.scheduleNextRun(job)
+		assert.Equal(t, time.Date(2020, time.January, 1, 9, 0, 0, 0, time.UTC), job.NextRun())
+	})
+}
+
+func TestScheduler_SetMaxConcurrentJobs(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	s.SetMaxConcurrentJobs(1)
+
+	var mu sync.Mutex
+	var running, maxRunning int
+	var order []string
+	newJob := func(name string, interval uint64) *Job {
+		job, err := s.Every(interval).Seconds().Do(func() {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			order = append(order, name)
+			mu.Unlock()
+			time.Sleep(50 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+		})
+		require.NoError(t, err)
+		return job
+	}
+	frequent := newJob("frequent", 1)
+	rare := newJob("rare", 3600)
+	now := time.Now()
+	frequent.setNextRun(now)
+	rare.setNextRun(now.Add(-time.Hour))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.run(frequent))
+		time.Sleep(5 * time.Millisecond)
+	}
+	require.NoError(t, s.run(rare))
+	s.GracefulStop()
+
+	assert.Equal(t, 1, maxRunning)
+	assert.Equal(t, []string{"frequent", "rare", "frequent", "frequent"}, order, "the longest waiting job should run first")
+}
+
+func TestScheduler_NextRunFunc(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("the delay doubles each run", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		now := start
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+		job, err := s.Every(1).Hour().Do(task)
+		require.NoError(t, err)
+		delay := time.Second
+		job.NextRunFunc(func(last time.Time) time.Time {
+			next := last.Add(delay)
+			delay *= 2
+			return next
+		})
+
+		s.scheduleNextRun(job)
+		assert.Equal(t, start, job.NextRun())
+		expected := []time.Time{start.Add(time.Second), start.Add(3 * time.Second), start.Add(7 * time.Second)}
+		for i := range expected {
+			now = job.NextRun()
+			job.setLastRun(now) // the job ran at its scheduled time
+			s.scheduleNextRun(job)
+			assert.Equal(t, expected[i], job.NextRun())
+		}
+	})
+
+	t.Run("a past next run falls back to the interval", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return start }}
+		job, err := s.Every(1).Hour().Do(task)
+		require.NoError(t, err)
+		job.NextRunFunc(func(last time.Time) time.Time { return last.Add(-time.Minute) })
+
+		job.setLastRun(start)
+		s.scheduleNextRun(job)
+		assert.Equal(t, start.Add(time.Hour), job.NextRun())
+	})
+}
+
+func TestScheduler_BlackoutWindow(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2020, time.January, day, hour, minute, 0, 0, time.UTC)
+	}
+	testCases := []struct {
+		desc     string
+		start    time.Time
+		windows  [][2]string
+		expected []time.Time
+	}{
+		{
+			desc:     "per minute job pauses during the window",
+			start:    at(1, 1, 58),
+			windows:  [][2]string{{"02:00", "02:30"}},
+			expected: []time.Time{at(1, 1, 58), at(1, 1, 59), at(1, 2, 30), at(1, 2, 31)},
+		},
+		{
+			desc:     "multiple windows",
+			start:    at(1, 1, 59),
+			windows:  [][2]string{{"02:00", "02:30"}, {"02:31", "03:00"}},
+			expected: []time.Time{at(1, 1, 59), at(1, 2, 30), at(1, 3, 0), at(1, 3, 1)},
+		},
+		{
+			desc:     "window spanning midnight",
+			start:    at(1, 23, 58),
+			windows:  [][2]string{{"23:59", "00:02"}},
+			expected: []time.Time{at(1, 23, 58), at(2, 0, 2), at(2, 0, 3)},
+		},
+		{
+			desc:     "job starting in the window waits for its end",
+			start:    at(1, 2, 10),
+			windows:  [][2]string{{"02:00", "02:30"}},
+			expected: []time.Time{at(1, 2, 30), at(1, 2, 31)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := NewScheduler(time.UTC)
+			now := tc.start
+			s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+			job, err := s.Every(1).Minute().Do(task)
+			require.NoError(t, err)
+			for _, window := range tc.windows {
+				require.NoError(t, job.BlackoutWindow(window[0], window[1]).Err())
+			}
+
+			var runs []time.Time
+			s.scheduleNextRun(job)
+			for range tc.expected {
+				now = job.NextRun()
+				runs = append(runs, now)
+				job.setLastRun(now)
+				s.scheduleNextRun(job)
+			}
+			assert.Equal(t, tc.expected, runs)
+		})
+	}
+}
+
+func TestScheduler_Critical(t *testing.T) {
+	errFailed := errors.New("failed")
+	waitStopped := func(s *Scheduler) bool {
+		for i := 0; i < 100 && s.IsRunning(); i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return !s.IsRunning()
+	}
+
+	t.Run("a critical failure stops the scheduler", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		job, err := s.Every(1).Second().Do(func() error { return errFailed })
+		require.NoError(t, err)
+		job.Critical()
+		failures := make(chan error, 1)
+		s.OnCriticalFailure(func(j *Job, err error) {
+			assert.Equal(t, job, j)
+			failures <- err
+		})
+
+		s.StartAsync()
+		select {
+		case err := <-failures:
+			assert.Equal(t, errFailed, err)
+		case <-time.After(3 * time.Second):
+			t.Fatal("the critical failure handler was not called")
+		}
+		assert.True(t, waitStopped(s))
+	})
+
+	t.Run("concurrent critical failures stop the scheduler once", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		var stops int32
+		s.OnStop(func() { atomic.AddInt32(&stops, 1) })
+		release := make(chan struct{})
+		for i := 0; i < 2; i++ {
+			job, err := s.Every(1).Hour().Do(func() error {
+				<-release
+				return errFailed
+			})
+			require.NoError(t, err)
+			job.Critical()
+		}
+
+		s.StartAsync()
+		for _, job := range s.Jobs() {
+			require.NoError(t, s.run(job))
+		}
+		close(release)
+		s.runningJobs.Wait()
+		assert.True(t, waitStopped(s))
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&stops))
+
+		// a leaked stop would take the signal of the next start
+		s.StartAsync()
+		time.Sleep(50 * time.Millisecond)
+		assert.True(t, s.IsRunning())
+		s.Stop()
+		assert.Equal(t, int32(2), atomic.LoadInt32(&stops))
+	})
+
+	t.Run("a normal failure doesn't stop the scheduler", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		job, err := s.Every(1).Second().Do(func() error { return errFailed })
+		require.NoError(t, err)
+		s.OnCriticalFailure(func(j *Job, err error) {
+			t.Error("the job is not critical")
+		})
+
+		s.StartAsync()
+		defer s.Stop()
+		require.NoError(t, s.WaitForRuns(1, 3*time.Second))
+		assert.Equal(t, errFailed, job.Err())
+		assert.False(t, waitStopped(s))
+	})
+}
+
+func TestScheduler_CalculateNearestRun(t *testing.T) {
+	day := 24 * time.Hour
+	// January 6th 2020 is a Monday
+	januaryAt := func(dayOfMonth, hour, minute int) time.Time {
+		return time.Date(2020, time.January, dayOfMonth, hour, minute, 0, 0, time.UTC)
+	}
+	at := func(hour, minute int) time.Duration {
+		return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+	}
+
+	testCases := []struct {
+		desc     string
+		unit     timeUnit
+		weekdays []time.Weekday
+		atTimes  []time.Duration
+		lastRun  time.Time
+		expected time.Duration
+	}{
+		{
+			desc:     "single day, single time",
+			unit:     weeks,
+			weekdays: []time.Weekday{time.Tuesday},
+			atTimes:  []time.Duration{at(9, 30)},
+			lastRun:  januaryAt(6, 10, 0),
+			expected: at(23, 30),
+		},
+		{
+			desc:     "multiple days, single time",
+			unit:     weeks,
+			weekdays: []time.Weekday{time.Monday, time.Thursday},
+			atTimes:  []time.Duration{at(9, 30)},
+			lastRun:  januaryAt(6, 10, 0),
+			expected: 3*day - at(0, 30),
+		},
+		{
+			desc:     "multiple days across the week boundary",
+			unit:     weeks,
+			weekdays: []time.Weekday{time.Monday, time.Saturday},
+			atTimes:  []time.Duration{at(9, 30)},
+			lastRun:  januaryAt(11, 10, 0),
+			expected: 2*day - at(0, 30),
+		},
+		{
+			desc:     "single day, multiple times later the same day",
+			unit:     weeks,
+			weekdays: []time.Weekday{time.Monday},
+			atTimes:  []time.Duration{at(9, 30), at(18, 0)},
+			lastRun:  januaryAt(6, 10, 0),
+			expected: at(8, 0),
+		},
+		{
+			desc:     "single day, multiple times all past",
+			unit:     weeks,
+			weekdays: []time.Weekday{time.Monday},
+			atTimes:  []time.Duration{at(9, 30), at(18, 0)},
+			lastRun:  januaryAt(6, 19, 0),
+			expected: 6*day + at(14, 30),
+		},
+		{
+			desc:     "multiple days and times",
+			unit:     weeks,
+			weekdays: []time.Weekday{time.Monday, time.Wednesday},
+			atTimes:  []time.Duration{at(9, 30), at(18, 0)},
+			lastRun:  januaryAt(6, 19, 0),
+			expected: day + at(14, 30),
+		},
+		{
+			desc:     "multiple days and times across midnight",
+			unit:     weeks,
+			weekdays: []time.Weekday{time.Monday, time.Wednesday},
+			atTimes:  []time.Duration{at(0, 30), at(18, 0)},
+			lastRun:  januaryAt(5, 23, 0),
+			expected: at(1, 30),
+		},
+		{
+			desc:     "daily, multiple times later the same day",
+			unit:     days,
+			atTimes:  []time.Duration{at(9, 30), at(18, 0)},
+			lastRun:  januaryAt(6, 10, 0),
+			expected: at(8, 0),
+		},
+		{
+			desc:     "daily, multiple times all past",
+			unit:     days,
+			atTimes:  []time.Duration{at(9, 30), at(18, 0)},
+			lastRun:  januaryAt(6, 19, 0),
+			expected: at(14, 30),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			s := NewScheduler(time.UTC)
+			job := &Job{interval: 1, unit: tc.unit, scheduledWeekdays: tc.weekdays, atTimes: tc.atTimes, lastRun: tc.lastRun}
+			assert.Equal(t, tc.expected, s.durationToNextRun(job))
+		})
+	}
+
+	t.Run("builders combine weekdays and times", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		job, err := s.Every(1).Wednesday().Monday().Wednesday().At("18:00", "09:30", "18:00").Do(task)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Weekday{time.Monday, time.Wednesday}, job.Weekdays())
+		assert.Equal(t, []string{"9:30", "18:0"}, job.ScheduledAtTimes())
+	})
+
+	t.Run("equivalent times are deduplicated", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		job, err := s.Every(1).Day().At("09:00", "9:00", "09:00:00").Do(task)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{9 * time.Hour}, job.getAtTimes())
+	})
+
+	t.Run("an invalid time among valid ones is rejected", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		_, err := s.Every(1).Day().At("09:00", "25:00", "10:00").Do(task)
+		assert.Equal(t, ErrTimeFormat, err)
+		assert.Zero(t, s.Len())
+	})
+}
+
+func TestScheduler_Schedule(t *testing.T) {
+	t.Run("jobs are chained", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		s.Every(1).Minute().Schedule(task).Every(2).Hours().Schedule(taskWithParams, 1, "hello")
+		require.NoError(t, s.Err())
+
+		jobs := s.Jobs()
+		require.Len(t, jobs, 2)
+		assert.Equal(t, minutes, jobs[0].unit)
+		assert.Equal(t, hours, jobs[1].unit)
+		assert.Equal(t, jobs[1], s.LastJob())
+	})
+
+	t.Run("the first error of the chain is kept", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		s.Every(1).Day().At("bad-time").Schedule(task).Every(0).Seconds().Schedule(task).Every(1).Hour().Schedule(task)
+		assert.Equal(t, ErrTimeFormat, s.Err())
+		assert.Len(t, s.Jobs(), 1)
+		assert.Equal(t, s.Jobs()[0], s.LastJob())
+	})
+}
+
+func TestScheduler_AfterFunc(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	var calls []string
+	job, err := s.AfterFunc(5*time.Second, func(name string) { calls = append(calls, name) }, "once")
+	require.NoError(t, err)
+	job.Sync()
+	assert.Equal(t, now.Add(5*time.Second), job.NextRun())
+
+	s.RunPending()
+	assert.Empty(t, calls, "the function must not run before the delay")
+
+	for i := 0; i < 3; i++ {
+		now = now.Add(5 * time.Second)
+		s.RunPending()
+	}
+	assert.Equal(t, []string{"once"}, calls)
+	assert.Empty(t, s.Jobs())
+
+	_, err = s.AfterFunc(time.Second, "not a function")
+	assert.Equal(t, ErrNotAFunction, err)
+	assert.Empty(t, s.Jobs())
+}
+
+type counterService struct {
+	count int
+}
+
+func (c *counterService) Add(n int) {
+	c.count += n
+}
+
+func TestScheduler_DoMethod(t *testing.T) {
+	t.Run("the method mutates its receiver", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		service := &counterService{}
+		job, err := s.Every(1).Second().DoMethod(service, "Add", 2)
+		require.NoError(t, err)
+
+		job.run()
+		job.run()
+		assert.Equal(t, 4, service.count)
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		job, err := s.Every(1).Second().DoMethod(&counterService{}, "Remove")
+		assert.Equal(t, ErrMethodNotFound, err)
+		assert.Nil(t, job)
+		assert.Zero(t, s.Len())
+	})
+
+	t.Run("nil receiver", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		_, err := s.Every(1).Second().DoMethod(nil, "Add", 2)
+		assert.Equal(t, ErrMethodNotFound, err)
+		var service *counterService
+		_, err = s.Every(1).Second().DoMethod(service, "Add", 2)
+		assert.Equal(t, ErrMethodNotFound, err)
+		assert.Zero(t, s.Len())
+	})
+
+	t.Run("params are validated", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		_, err := s.Every(1).Second().DoMethod(&counterService{}, "Add", "two")
+		assert.Equal(t, ErrParamTypeMismatch, err)
+	})
+}
+
+func TestScheduler_OnStartOnStop(t *testing.T) {
+	t.Run("both fire exactly once", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		var starts, stops int32
+		s.OnStart(func() { atomic.AddInt32(&starts, 1) })
+		s.OnStop(func() { atomic.AddInt32(&stops, 1) })
+
+		s.StartAsync()
+		s.StartAsync()
+		s.Stop()
+		s.Stop()
+		assert.Equal(t, int32(1), atomic.LoadInt32(&starts))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&stops))
+	})
+
+	t.Run("OnStop fires after the running jobs drain on graceful stop", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		var finished int32
+		_, err := s.Every(1).Second().Do(func() {
+			time.Sleep(200 * time.Millisecond)
+			atomic.StoreInt32(&finished, 1)
+		})
+		require.NoError(t, err)
+		var finishedOnStop int32 = -1
+		s.OnStop(func() { finishedOnStop = atomic.LoadInt32(&finished) })
+
+		s.StartAsync()
+		require.NoError(t, s.WaitForRuns(1, 3*time.Second))
+		s.GracefulStop()
+		assert.Equal(t, int32(1), finishedOnStop)
+	})
+}
+
+func TestScheduler_IntervalByWeekday(t *testing.T) {
+	// January 3rd 2020 is a Friday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2020, time.January, day, hour, minute, 0, 0, time.UTC)
+	}
+	s := NewScheduler(time.UTC)
+	now := at(3, 23, 50)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	job, err := s.Every(5).Minutes().Do(task)
+	require.NoError(t, err)
+	require.NoError(t, job.IntervalByWeekday(map[time.Weekday]uint64{time.Saturday: 30, time.Sunday: 30}).Err())
+
+	var runs []time.Time
+	s.scheduleNextRun(job)
+	for i := 0; i < 5; i++ {
+		now = job.NextRun()
+		runs = append(runs, now)
+		job.setLastRun(now)
+		s.scheduleNextRun(job)
+	}
+	assert.Equal(t, []time.Time{at(3, 23, 50), at(3, 23, 55), at(4, 0, 0), at(4, 0, 30), at(4, 1, 0)}, runs)
+
+	t.Run("zero intervals are rejected", func(t *testing.T) {
+		job, err := NewScheduler(time.UTC).Every(5).Minutes().Do(task)
+		require.NoError(t, err)
+		assert.Equal(t, ErrZeroInterval, job.IntervalByWeekday(map[time.Weekday]uint64{time.Monday: 0}).Err())
+	})
+}
+
+func TestScheduler_Phase(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 10, 5, 0, 0, time.UTC)
+	now := start
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	newJob := func(offset time.Duration) *Job {
+		job, err := s.Every(1).Hour().Do(func() {})
+		require.NoError(t, err)
+		job.Phase(offset)
+		s.scheduleNextRun(job)
+		return job
+	}
+	at20, at40 := newJob(20*time.Minute), newJob(40*time.Minute)
+	assert.Equal(t, time.Date(2020, time.January, 1, 10, 20, 0, 0, time.UTC), at20.NextRun())
+	assert.Equal(t, time.Date(2020, time.January, 1, 10, 40, 0, 0, time.UTC), at40.NextRun())
+
+	// the runs start a little late, as on a tick of the scheduler
+	for _, job := range []*Job{at20, at40} {
+		for hour := 11; hour <= 12; hour++ {
+			now = job.NextRun().Add(time.Second)
+			job.setLastRun(now)
+			s.scheduleNextRun(job)
+			assert.Equal(t, hour, job.NextRun().Hour())
+		}
+	}
+	assert.Equal(t, time.Date(2020, time.January, 1, 12, 20, 0, 0, time.UTC), at20.NextRun())
+	assert.Equal(t, time.Date(2020, time.January, 1, 12, 40, 0, 0, time.UTC), at40.NextRun())
+
+	t.Run("an interval not dividing the day restarts at midnight", func(t *testing.T) {
+		now = time.Date(2020, time.January, 1, 21, 30, 0, 0, time.UTC)
+		job, err := s.Every(5).Hours().Do(func() {})
+		require.NoError(t, err)
+		s.scheduleNextRun(job.Phase(time.Hour))
+		assert.Equal(t, time.Date(2020, time.January, 2, 1, 0, 0, 0, time.UTC), job.NextRun())
+	})
+}
+
+func TestScheduler_StartDelay(t *testing.T) {
+	now := time.Date(2020, time.January, 3, 8, 59, 59, 0, time.UTC)
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	job, err := s.Every(1).Second().Do(task)
+	require.NoError(t, err)
+	s.scheduleNextRun(job.StartDelay(2 * time.Second))
+	assert.Equal(t, now.Add(2*time.Second), job.NextRun())
+
+	start := now
+	now = start.Add(time.Second)
+	assert.False(t, s.shouldRun(job))
+	now = start.Add(2 * time.Second)
+	assert.True(t, s.shouldRun(job))
+
+	t.Run("scheduled first run within the delay", func(t *testing.T) {
+		now = start
+		job, err := s.Every(1).Day().At("09:00").Do(task)
+		require.NoError(t, err)
+		job.StartDelay(2 * time.Second)
+		s.scheduleNextRun(job)
+		assert.Equal(t, start.Add(2*time.Second), job.NextRun())
+	})
+
+	t.Run("scheduled first run after the delay", func(t *testing.T) {
+		now = start
+		job, err := s.Every(1).Day().At("10:00").Do(task)
+		require.NoError(t, err)
+		job.StartDelay(2 * time.Second)
+		s.scheduleNextRun(job)
+		assert.Equal(t, time.Date(2020, time.January, 3, 10, 0, 0, 0, time.UTC), job.NextRun())
+	})
+}
+
+func TestScheduler_LimitDurationTo(t *testing.T) {
+	start := time.Date(2020, time.January, 3, 9, 0, 0, 0, time.UTC)
+	now := start
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	job, err := s.Every(10).Seconds().Do(task)
+	require.NoError(t, err)
+	job.Sync()
+	job.LimitDurationTo(time.Minute)
+	s.scheduleNextRun(job)
+
+	for now.Before(start.Add(2 * time.Minute)) {
+		s.RunPending()
+		now = now.Add(10 * time.Second)
+	}
+	assert.Equal(t, 6, job.RunCount())
+	assert.Equal(t, 0, s.Len())
+
+	t.Run("run limit reached first", func(t *testing.T) {
+		now = start
+		s := NewScheduler(time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+		job, err := s.Every(10).Seconds().Do(task)
+		require.NoError(t, err)
+		job.Sync()
+		job.LimitDurationTo(time.Minute)
+		job.LimitRunsTo(2)
+		s.scheduleNextRun(job)
+
+		for now.Before(start.Add(time.Minute)) {
+			s.RunPending()
+			now = now.Add(10 * time.Second)
+		}
+		assert.Equal(t, 2, job.RunCount())
+	})
+}
+
+func TestScheduler_OnReschedule(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 20, 0, time.UTC)
+	now := start
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	job, err := s.Every(90).Seconds().Do(task)
+	require.NoError(t, err)
+	job.OnReschedule(func(current time.Time) time.Time {
+		if current.Truncate(time.Minute).Equal(current) {
+			return time.Time{}
+		}
+		return current.Truncate(time.Minute).Add(time.Minute)
+	})
+
+	s.scheduleNextRun(job)
+	assert.Equal(t, start, job.NextRun(), "the immediate first run is not adjusted")
+	now = job.NextRun()
+	job.setLastRun(now)
+	s.scheduleNextRun(job)
+	assert.Equal(t, time.Date(2020, time.January, 1, 0, 2, 0, 0, time.UTC), job.NextRun())
+	assert.Contains(t, job.ExplainNextRun(), "adjusted from 2020-01-01T00:01:50Z by OnReschedule")
+
+	now = job.NextRun()
+	job.setLastRun(now)
+	s.scheduleNextRun(job)
+	assert.Equal(t, time.Date(2020, time.January, 1, 0, 4, 0, 0, time.UTC), job.NextRun())
+}
+
+func TestScheduler_NoFuncSet(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	s.Every(1).Second()
+	noFunc := s.getCurrentJob()
+	noFunc.LimitRunsTo(1)
+	var completions int
+	noFunc.OnComplete(func() { completions++ })
+	_, err := s.Every(1).Second().Do(func() {})
+	require.NoError(t, err)
+	assert.Equal(t, ErrNoFuncSet, noFunc.Err(), "the error should be reported once the next job is scheduled")
+
+	assert.NotPanics(t, func() {
+		s.StartAsync()
+		require.NoError(t, s.WaitForRuns(1, 3*time.Second))
+		s.Stop()
+	})
+	assert.Equal(t, ErrNoFuncSet, noFunc.Err())
+	assert.Equal(t, 0, noFunc.RunCount())
+
+	assert.NotPanics(t, func() { s.RunAll() })
+	s.runningJobs.Wait()
+	assert.Equal(t, ErrNoFuncSet, noFunc.Err())
+	assert.Equal(t, 0, noFunc.RunCount(), "a manual run shouldn't be counted")
+	assert.Zero(t, noFunc.ErrorCount())
+	assert.Zero(t, completions)
+}
+
+func TestScheduler_ISOWeekParity(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+	}
+	mondays := func(parity bool, from time.Time, n int) []time.Time {
+		s := NewScheduler(time.UTC)
+		now := from
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+		job, err := s.Every(1).Monday().At("09:00").Do(task)
+		require.NoError(t, err)
+		job.ISOWeekParity(parity)
+
+		var runs []time.Time
+		s.scheduleNextRun(job)
+		for i := 0; i < n; i++ {
+			runs = append(runs, job.NextRun())
+			now = job.NextRun().Add(time.Second) // the run started once due
+			s.scheduleNextRun(job)
+		}
+		return runs
+	}
+
+	// 2020 has 53 ISO weeks: December 28th 2020 is in week 53 and January 4th 2021 in week 1
+	assert.Equal(t,
+		[]time.Time{date(2020, time.December, 14), date(2020, time.December, 28), date(2021, time.January, 4), date(2021, time.January, 18)},
+		mondays(true, date(2020, time.December, 8), 4))
+	assert.Equal(t,
+		[]time.Time{date(2020, time.December, 21), date(2021, time.January, 11), date(2021, time.January, 25)},
+		mondays(false, date(2020, time.December, 8), 3))
+
+	// 2021 has 52 ISO weeks: December 27th 2021 is in week 52 and January 3rd 2022 in week 1
+	assert.Equal(t,
+		[]time.Time{date(2021, time.December, 27), date(2022, time.January, 10)},
+		mondays(false, date(2021, time.December, 21), 2))
+	assert.Equal(t,
+		[]time.Time{date(2022, time.January, 3), date(2022, time.January, 17)},
+		mondays(true, date(2021, time.December, 21), 2))
+}
+
+func TestScheduler_SetDefaultTimeout(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	s.SetDefaultTimeout(50 * time.Millisecond)
+	wait := func(ctx context.Context) {
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+	}
+
+	inheriting, err := s.Every(1).Hour().Do(wait)
+	require.NoError(t, err)
+	inheriting.Sync()
+	own, err := s.Every(1).Hour().Do(wait)
+	require.NoError(t, err)
+	own.Sync()
+	own.SetTimeout(200 * time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, s.run(inheriting))
+	assert.Equal(t, ErrRunTimeout, inheriting.Err())
+	assert.True(t, time.Since(start) < 200*time.Millisecond, "the default timeout should apply")
+
+	start = time.Now()
+	require.NoError(t, s.run(own))
+	assert.Equal(t, ErrRunTimeout, own.Err())
+	assert.True(t, time.Since(start) >= 200*time.Millisecond, "the job's own timeout should override the default")
+}
+
+func TestScheduler_ForEach(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	var jobs []*Job
+	for i := 0; i < 5; i++ {
+		job, err := s.Every(1).Minute().Do(func() {})
+		require.NoError(t, err)
+		jobs = append(jobs, job)
+	}
+
+	var visited []*Job
+	s.ForEach(func(job *Job) bool {
+		visited = append(visited, job)
+		return true
+	})
+	assert.Equal(t, jobs, visited)
+
+	visited = nil
+	s.ForEach(func(job *Job) bool {
+		visited = append(visited, job)
+		return len(visited) < 2
+	})
+	assert.Equal(t, jobs[:2], visited, "the iteration should stop once f returns false")
+}
+
+func TestScheduler_JobsByNextRun(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	newJob := func(interval uint64) *Job {
+		job, err := s.Every(interval).Minutes().Do(func() {})
+		require.NoError(t, err)
+		job.setLastRun(now)
+		s.scheduleNextRun(job)
+		return job
+	}
+	hourly, minutely, paused, quarterly := newJob(60), newJob(1), newJob(2), newJob(15)
+	notScheduled, err := s.Every(1).Minute().Do(func() {})
+	require.NoError(t, err)
+	paused.Pause()
+
+	assert.Equal(t, []*Job{minutely, quarterly, hourly, notScheduled, paused}, s.JobsByNextRun())
+	assert.Equal(t, []*Job{hourly, minutely, paused, quarterly, notScheduled}, s.Jobs())
+}
+
+func TestScheduler_RunCountInRange(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 8, 0, 0, 0, time.UTC)
+	s := NewScheduler(time.UTC)
+
+	job, err := s.Every(15).Minutes().Do(task)
+	require.NoError(t, err)
+	assert.Equal(t, 8, s.RunCountInRange(job, start, start.Add(2*time.Hour)))
+	assert.Zero(t, job.RunCount())
+	assert.True(t, job.NextRun().IsZero(), "the job should be left untouched")
+	assert.True(t, job.LastRun().IsZero(), "the job should be left untouched")
+
+	job.BlackoutWindow("09:00", "09:30")
+	assert.Equal(t, 6, s.RunCountInRange(job, start, start.Add(2*time.Hour)))
+
+	// January 1st 2020 is a Wednesday
+	weekly, err := s.Every(1).Monday().Thursday().At("09:00").Do(task)
+	require.NoError(t, err)
+	assert.Equal(t, 8, s.RunCountInRange(weekly, start, start.AddDate(0, 0, 28)))
+
+	monthly, err := s.Every(1).Month(1).At("08:00").Do(task)
+	require.NoError(t, err)
+	assert.Equal(t, 3, s.RunCountInRange(monthly, start, start.AddDate(0, 3, 0)))
+
+	// late days of the month, spilling over to the next month when it is shorter
+	// with Month, and skipped with DaysOfMonth
+	lateDay, err := s.Every(1).Month(30).Do(task)
+	require.NoError(t, err)
+	assert.Equal(t, 12, s.RunCountInRange(lateDay, start, start.AddDate(1, 0, 0)))
+	lastDay, err := s.Every(1).DaysOfMonth(31).Do(task)
+	require.NoError(t, err)
+	assert.Equal(t, 7, s.RunCountInRange(lastDay, start, start.AddDate(1, 0, 0)))
+
+	everySecond, err := s.Every(1).Second().Do(task)
+	require.NoError(t, err)
+	assert.Equal(t, 366*24*60*60, s.RunCountInRange(everySecond, start, start.AddDate(1, 0, 0)))
+	everySecond.Between("08:00", "09:00")
+	assert.Equal(t, 2*(60*60+1), s.RunCountInRange(everySecond, start, start.AddDate(0, 0, 2)))
+
+	assert.Zero(t, s.RunCountInRange(job, start, start))
+}
+
+func TestScheduler_NextRunAfter(t *testing.T) {
+	// January 1st 2020 is a Wednesday
+	now := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+	at := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2020, month, day, hour, min, 0, 0, time.UTC)
+	}
+
+	t.Run("interval", func(t *testing.T) {
+		job, err := s.Every(15).Minutes().Do(func() {})
+		require.NoError(t, err)
+		s.scheduleNextRun(job)
+		require.Equal(t, now, job.NextRun())
+
+		assert.Equal(t, at(time.January, 1, 10, 45), s.NextRunAfter(job, at(time.January, 1, 10, 37)))
+		assert.Equal(t, at(time.January, 1, 10, 45), s.NextRunAfter(job, at(time.January, 1, 10, 45)))
+		assert.Equal(t, at(time.January, 1, 9, 30), s.NextRunAfter(job, at(time.January, 1, 9, 20)))
+		assert.Equal(t, now, job.NextRun(), "the job should be left untouched")
+	})
+
+	t.Run("weekday", func(t *testing.T) {
+		job, err := s.Every(1).Monday().At("09:00").Do(func() {})
+		require.NoError(t, err)
+		assert.Equal(t, at(time.January, 6, 9, 0), s.NextRunAfter(job, at(time.January, 1, 12, 0)))
+		assert.Equal(t, at(time.January, 6, 9, 0), s.NextRunAfter(job, at(time.January, 6, 9, 0)))
+
+		s.scheduleNextRun(job)
+		require.Equal(t, at(time.January, 6, 9, 0), job.NextRun())
+		assert.Equal(t, at(time.January, 27, 9, 0), s.NextRunAfter(job, at(time.January, 22, 0, 0)))
+	})
+
+	t.Run("monthly", func(t *testing.T) {
+		job, err := s.Every(1).Month(15).At("08:00").Do(func() {})
+		require.NoError(t, err)
+		assert.Equal(t, at(time.February, 15, 8, 0), s.NextRunAfter(job, at(time.January, 20, 0, 0)))
+		assert.Equal(t, at(time.January, 15, 8, 0), s.NextRunAfter(job, at(time.January, 15, 7, 0)))
+	})
+}
+
+func TestScheduler_Backfill(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	s := NewScheduler(time.UTC)
+
+	var days []time.Time
+	daily, err := s.Every(1).Day().At("06:00").Do(func(day time.Time, _ string) { days = append(days, day) }, "report")
+	require.NoError(t, err)
+	require.NoError(t, s.Backfill(daily, start, start.AddDate(0, 0, 7)))
+	require.Len(t, days, 7)
+	for i, day := range days {
+		assert.Equal(t, time.Date(2020, time.January, 1+i, 6, 0, 0, 0, time.UTC), day)
+	}
+	assert.Zero(t, daily.RunCount())
+	assert.True(t, daily.LastRun().IsZero(), "the job should be left untouched")
+
+	errFailed := errors.New("failed")
+	var runs int
+	failing, err := s.Every(1).Hour().Do(func(ctx context.Context, at time.Time) error {
+		runs++
+		if at.Hour() == 2 {
+			return errFailed
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	err = s.Backfill(failing, start, start.AddDate(0, 0, 1))
+	assert.True(t, errors.Is(err, errFailed))
+	assert.Equal(t, 3, runs, "the backfill should stop at the first error")
+
+	noTime, err := s.Every(1).Day().Do(func() {})
+	require.NoError(t, err)
+	assert.Equal(t, ErrNoRunTimeParam, s.Backfill(noTime, start, start.AddDate(0, 0, 7)))
+
+	t.Run("monthly on a late day", func(t *testing.T) {
+		var monthlyRuns []time.Time
+		monthly, err := s.Every(1).Month(30).Do(func(at time.Time) { monthlyRuns = append(monthlyRuns, at) })
+		require.NoError(t, err)
+		require.NoError(t, s.Backfill(monthly, start, start.AddDate(0, 4, 0)))
+		assert.Equal(t, []time.Time{
+			time.Date(2020, time.January, 30, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, time.March, 30, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, time.April, 30, 0, 0, 0, 0, time.UTC),
+		}, monthlyRuns)
+	})
+}
+
+func TestScheduler_SetTimeScale(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+	s.SetTimeScale(60)
+
+	job, err := s.Every(1).Minute().Do(task)
+	require.NoError(t, err)
+	daily, err := s.Every(1).Day().At("09:00").Do(task)
+	require.NoError(t, err)
+	s.scheduleNextRun(job)
+	s.scheduleNextRun(daily)
+	for i := 1; i <= 3; i++ {
+		now = job.NextRun()
+		job.setLastRun(now)
+		s.scheduleNextRun(job)
+		assert.Equal(t, start.Add(time.Duration(i)*time.Second), job.NextRun())
+	}
+	assert.Equal(t, start.Add(9*time.Hour), daily.NextRun(), "the runs at specific times are not scaled")
+
+	s.SetTimeScale(0)
+	now = job.NextRun()
+	job.setLastRun(now)
+	s.scheduleNextRun(job)
+	assert.Equal(t, now.Add(time.Minute), job.NextRun())
+
+	t.Run("a minute job runs every second at 60x", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		s.SetTimeScale(60)
+		_, err := s.Every(1).Minute().Do(func() {})
+		require.NoError(t, err)
+		s.StartAsync()
+		defer s.Stop()
+		assert.NoError(t, s.WaitForRuns(3, 4*time.Second))
+	})
+}
+
+func TestScheduler_Validate(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	// every 12 months from February, which never has a 31st
+	now := time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	_, err := s.Every(1).Minute().Do(task)
+	require.NoError(t, err)
+	assert.Empty(t, s.Validate())
+
+	february, err := s.Every(12).DaysOfMonth(31).Do(task)
+	require.NoError(t, err)
+	february.SetName("february")
+	s.Every(1).Hour() // Do is never called
+	noUnit, err := s.Every(1).Do(task)
+	require.NoError(t, err)
+	noUnit.SetName("no unit")
+
+	errs := s.Validate()
+	require.Len(t, errs, 3)
+	assert.True(t, errors.Is(errs[0], ErrNeverScheduled))
+	assert.Equal(t, "job 1 (february): the job is never scheduled to run", errs[0].Error())
+	assert.True(t, errors.Is(errs[1], ErrNoFuncSet))
+	assert.True(t, errors.Is(errs[2], ErrPeriodNotSpecified))
+	assert.Contains(t, errs[2].Error(), "job 3 (no unit)")
+}
+
+func TestScheduler_ResetJob(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+	s.setRunning(true)
+	job, err := s.Every(1).Hour().Do(func() error { return errors.New("failed") })
+	require.NoError(t, err)
+	job.Sync()
+	for i := 0; i < 2; i++ {
+		now = job.NextRun()
+		require.NoError(t, s.runAndReschedule(job))
+	}
+	require.Equal(t, 2, job.RunCount())
+	require.Error(t, job.Err())
+
+	now = start.Add(48 * time.Hour)
+	s.ResetJob(job)
+	assert.Equal(t, 0, job.RunCount())
+	assert.Equal(t, 0, job.ErrorCount())
+	assert.NoError(t, job.Err())
+	assert.True(t, job.LastRun().IsZero())
+	assert.Equal(t, now, job.NextRun(), "the job should start afresh, without catching up")
+
+	t.Run("stopped scheduler", func(t *testing.T) {
+		s.setRunning(false)
+		s.ResetJob(job)
+		assert.True(t, job.NextRun().IsZero(), "the next run should be scheduled on start")
+	})
+}
+
+func TestScheduler_SetStartupStagger(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+	s.SetStartupStagger(time.Second)
+	var jobs []*Job
+	for i := 0; i < 5; i++ {
+		job, err := s.Every(1).Hour().Do(task)
+		require.NoError(t, err)
+		jobs = append(jobs, job)
+	}
+	later, err := s.Every(1).Day().At("12:00").Do(task)
+	require.NoError(t, err)
+
+	s.scheduleAllJobs()
+	for i, job := range jobs {
+		assert.Equal(t, now.Add(time.Duration(i)*time.Second), job.NextRun(), "job %d", i)
+	}
+	assert.Equal(t, time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC), later.NextRun(), "jobs not starting immediately shouldn't be staggered")
+
+	// the next runs are scheduled from the staggered first run
+	firstRun := jobs[3].NextRun()
+	now = firstRun
+	jobs[3].setLastRun(firstRun)
+	s.scheduleNextRun(jobs[3])
+	assert.Equal(t, firstRun.Add(time.Hour), jobs[3].NextRun())
+}
+
+func TestScheduler_SetWeekStart(t *testing.T) {
+	// Sunday, January 5th 2020 is the last day of ISO week 1
+	lastRun := time.Date(2020, time.January, 5, 10, 0, 1, 0, time.UTC)
+	nextRun := func(t *testing.T, weekStart *time.Weekday) time.Time {
+		s := NewScheduler(time.UTC)
+		if weekStart != nil {
+			s.SetWeekStart(*weekStart)
+		}
+		job, err := s.Every(2).Sunday().Monday().At("10:00").Do(task)
+		require.NoError(t, err)
+		job.setLastRun(lastRun)
+		return lastRun.Add(s.durationToNextRun(job))
+	}
+	sunday, monday := time.Sunday, time.Monday
+	assert.Equal(t, time.Date(2020, time.January, 6, 10, 0, 0, 0, time.UTC), nextRun(t, &sunday),
+		"the Monday should be in the week of the Sunday")
+	assert.Equal(t, time.Date(2020, time.January, 13, 10, 0, 0, 0, time.UTC), nextRun(t, &monday),
+		"the Sunday should end the week, the next run being 2 weeks after its Monday")
+	assert.Equal(t, time.Date(2020, time.January, 13, 10, 0, 0, 0, time.UTC), nextRun(t, nil),
+		"the weeks should start on Monday by default")
+
+	t.Run("weekdays alternate by default", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		job, err := s.Every(2).Monday().Thursday().Do(task)
+		require.NoError(t, err)
+		// Monday, January 6th 2020
+		run := time.Date(2020, time.January, 6, 0, 0, 1, 0, time.UTC)
+		var runs []time.Time
+		for i := 0; i < 4; i++ {
+			job.setLastRun(run)
+			run = run.Add(s.durationToNextRun(job))
+			runs = append(runs, run)
+		}
+		assert.Equal(t, []time.Time{
+			time.Date(2020, time.January, 9, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, time.January, 20, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, time.January, 23, 0, 0, 0, 0, time.UTC),
+			time.Date(2020, time.February, 3, 0, 0, 0, 0, time.UTC),
+		}, runs)
+	})
+
+	t.Run("week parity", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		now := time.Date(2019, time.December, 29, 10, 0, 1, 0, time.UTC)
+		s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+		job, err := s.Every(1).Sunday().At("10:00").Do(task)
+		require.NoError(t, err)
+		job.ISOWeekParity(true)
+		job.setLastRun(now)
+		s.scheduleNextRun(job)
+		assert.Equal(t, time.Date(2020, time.January, 5, 10, 0, 0, 0, time.UTC), job.NextRun(), "Sunday ends odd ISO week 1")
+
+		s.SetWeekStart(time.Sunday)
+		s.scheduleNextRun(job)
+		assert.Equal(t, time.Date(2020, time.January, 12, 10, 0, 0, 0, time.UTC), job.NextRun(), "Sunday starts the week of even ISO week 2")
+	})
+}
@@ -0,0 +1,342 @@
+package gocron
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// JobSnapshot is a serializable representation of a Job's configuration
+// and run state, as produced by Scheduler.Export
+type JobSnapshot struct {
+	Interval           uint64            `json:"interval"`
+	Unit               string            `json:"unit,omitempty"`
+	AtTimes            []time.Duration   `json:"atTimes,omitempty"`
+	StartsImmediately  bool              `json:"startsImmediately"`
+	Weekdays           []time.Weekday    `json:"weekdays,omitempty"`
+	DaysOfTheMonth     []int             `json:"daysOfTheMonth,omitempty"`
+	SkipShortMonths    bool              `json:"skipShortMonths,omitempty"` // if set with DaysOfMonth, skipping the days a month doesn't have
+	Jitter             time.Duration     `json:"jitter,omitempty"`
+	ExcludedDates      []time.Time       `json:"excludedDates,omitempty"`
+	DailyWindow        []time.Duration   `json:"dailyWindow,omitempty"`     // start and end of the window set with Between
+	BlackoutWindows    [][]time.Duration `json:"blackoutWindows,omitempty"` // start and end of the windows set with BlackoutWindow
+	ISOWeekParity      string            `json:"isoWeekParity,omitempty"`   // "odd" or "even" if set with ISOWeekParity
+	Func               string            `json:"func"`
+	Name               string            `json:"name,omitempty"`
+	Params             []interface{}     `json:"params,omitempty"`
+	Tags               []string          `json:"tags,omitempty"`
+	Labels             map[string]string `json:"labels,omitempty"`
+	FiniteRuns         bool              `json:"finiteRuns,omitempty"`
+	MaxRuns            int               `json:"maxRuns,omitempty"`
+	MaxDuration        time.Duration     `json:"maxDuration,omitempty"`
+	Mode               Mode              `json:"mode,omitempty"`
+	RemoveAfterLastRun bool              `json:"removeAfterLastRun,omitempty"`
+	Sync               bool              `json:"sync,omitempty"`
+	RunCount           int               `json:"runCount"`
+	LastRun            time.Time         `json:"lastRun"`
+	LastSuccessfulRun  time.Time         `json:"lastSuccessfulRun"`
+	NextRun            time.Time         `json:"nextRun"`
+}
+
+// Snapshot returns the serializable representation of the Job
+func (j *Job) Snapshot() JobSnapshot {
+	j.RLock()
+	defer j.RUnlock()
+	snap := JobSnapshot{
+		Interval:           uint64(j.interval),
+		Unit:               j.unit.String(),
+		AtTimes:            append([]time.Duration{}, j.atTimes...),
+		Weekdays:           append([]time.Weekday{}, j.scheduledWeekdays...),
+		StartsImmediately:  j.startsImmediately,
+		DaysOfTheMonth:     append([]int{}, j.daysOfTheMonth...),
+		SkipShortMonths:    j.skipsShortMonths,
+		Jitter:             j.jitter,
+		ExcludedDates:      append([]time.Time{}, j.excludedDates...),
+		Func:               j.jobFunc,
+		Name:               j.name,
+		Params:             append([]interface{}{}, j.fparams[j.jobFunc]...),
+		Tags:               append([]string{}, j.tags...),
+		Labels:             make(map[string]string, len(j.labels)),
+		FiniteRuns:         j.runConfig.finiteRuns,
+		MaxRuns:            j.runConfig.maxRuns,
+		MaxDuration:        j.runConfig.maxDuration,
+		Mode:               j.runConfig.mode,
+		RemoveAfterLastRun: j.runConfig.removeAfterLastRun,
+		Sync:               j.runConfig.sync,
+		RunCount:           j.runCount,
+		LastRun:            j.lastRun,
+		LastSuccessfulRun:  j.lastSuccessfulRun,
+		NextRun:            j.nextRun,
+	}
+	for key, value := range j.labels {
+		snap.Labels[key] = value
+	}
+	if j.dailyWindow != nil {
+		snap.DailyWindow = []time.Duration{j.dailyWindow.start, j.dailyWindow.end}
+	}
+	snap.ISOWeekParity = j.weekParity.String()
+	for _, window := range j.blackoutWindows {
+		snap.BlackoutWindows = append(snap.BlackoutWindows, []time.Duration{window.start, window.end})
+	}
+	return snap
+}
+
+// Schedule is the definition of when a Job runs, as returned by GetSchedule.
+// Unlike JobSnapshot it holds no run state, and the times of the day are
+// formatted like the ones given to At
+type Schedule struct {
+	Name        string         `json:"name,omitempty"` // name of the Job, identifying it in Diff
+	Interval    uint64         `json:"interval"`
+	Unit        string         `json:"unit,omitempty"`
+	AtTimes     []string       `json:"atTimes,omitempty"`
+	Weekdays    []time.Weekday `json:"weekdays,omitempty"`
+	DaysOfMonth []int          `json:"daysOfMonth,omitempty"`
+}
+
+// GetSchedule returns the definition of when the Job runs
+func (j *Job) GetSchedule() Schedule {
+	j.RLock()
+	defer j.RUnlock()
+	schedule := Schedule{
+		Name:        j.name,
+		Interval:    uint64(j.interval),
+		Unit:        j.unit.String(),
+		Weekdays:    append([]time.Weekday(nil), j.scheduledWeekdays...),
+		DaysOfMonth: append([]int(nil), j.daysOfTheMonth...),
+	}
+	for _, atTime := range j.atTimes {
+		clock := fmt.Sprintf("%02d:%02d", atTime/time.Hour, atTime%time.Hour/time.Minute)
+		if seconds := atTime % time.Minute / time.Second; seconds != 0 {
+			clock += fmt.Sprintf(":%02d", seconds)
+		}
+		schedule.AtTimes = append(schedule.AtTimes, clock)
+	}
+	return schedule
+}
+
+// Diff compares the schedules of the named Jobs of the Scheduler with the
+// desired ones, matched by name, e.g. for a reconciler to apply a declarative
+// configuration. It returns the desired schedules without a Job, the desired
+// schedules differing from the one of their Job, and the schedules of the
+// Jobs without a desired schedule. The schedules are compared on their
+// definition, regardless of the order of their times and days. The Jobs and
+// the desired schedules without a name are ignored, as they can't be matched
+func (s *Scheduler) Diff(desired []Schedule) (toAdd, toUpdate, toRemove []Schedule) {
+	current := make(map[string]Schedule)
+	var names []string
+	for _, job := range s.Jobs() {
+		schedule := job.GetSchedule()
+		if _, ok := current[schedule.Name]; ok || schedule.Name == "" {
+			continue
+		}
+		current[schedule.Name] = schedule
+		names = append(names, schedule.Name)
+	}
+	wanted := make(map[string]bool, len(desired))
+	for _, schedule := range desired {
+		if schedule.Name == "" {
+			continue
+		}
+		wanted[schedule.Name] = true
+		existing, ok := current[schedule.Name]
+		switch {
+		case !ok:
+			toAdd = append(toAdd, schedule)
+		case !sameSchedule(existing, schedule):
+			toUpdate = append(toUpdate, schedule)
+		}
+	}
+	for _, name := range names {
+		if !wanted[name] {
+			toRemove = append(toRemove, current[name])
+		}
+	}
+	return toAdd, toUpdate, toRemove
+}
+
+// sameSchedule returns true if the schedules define the same runs
+func sameSchedule(a, b Schedule) bool {
+	if a.Interval != b.Interval || a.Unit != b.Unit {
+		return false
+	}
+	weekdays := func(schedule Schedule) []int {
+		days := make([]int, 0, len(schedule.Weekdays))
+		for _, weekday := range schedule.Weekdays {
+			days = append(days, int(weekday))
+		}
+		return days
+	}
+	atTimes := func(schedule Schedule) []int {
+		times := make([]int, 0, len(schedule.AtTimes))
+		for _, t := range schedule.AtTimes {
+			atTime, err := ParseAtTime(t)
+			if err != nil {
+				atTime = -1
+			}
+			times = append(times, int(atTime/time.Second))
+		}
+		return times
+	}
+	return sameInts(weekdays(a), weekdays(b)) && sameInts(a.DaysOfMonth, b.DaysOfMonth) &&
+		sameInts(atTimes(a), atTimes(b))
+}
+
+// sameInts returns true if a and b hold the same values, in any order
+func sameInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = append([]int(nil), a...), append([]int(nil), b...)
+	sort.Ints(a)
+	sort.Ints(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// newJobFromSnapshot rebuilds a Job from its snapshot, resolving the
+// job function by name from the registry
+func newJobFromSnapshot(snap JobSnapshot, registry map[string]interface{}) (*Job, error) {
+	jobFun, ok := registry[snap.Func]
+	if !ok {
+		return nil, ErrFuncNotRegistered
+	}
+	if err := validateJobFunc(jobFun, snap.Params); err != nil {
+		return nil, err
+	}
+	unit, err := parseTimeUnit(snap.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	j := NewJob(snap.Interval)
+	j.unit = unit
+	j.atTimes = append([]time.Duration{}, snap.AtTimes...)
+	j.startsImmediately = snap.StartsImmediately
+	j.scheduledWeekdays = append([]time.Weekday{}, snap.Weekdays...)
+	j.daysOfTheMonth = append([]int{}, snap.DaysOfTheMonth...)
+	j.skipsShortMonths = snap.SkipShortMonths
+	j.jitter = snap.Jitter
+	j.excludedDates = append([]time.Time{}, snap.ExcludedDates...)
+	if len(snap.DailyWindow) == 2 {
+		j.dailyWindow = &timeWindow{start: snap.DailyWindow[0], end: snap.DailyWindow[1]}
+	}
+	for _, window := range snap.BlackoutWindows {
+		if len(window) == 2 {
+			j.blackoutWindows = append(j.blackoutWindows, timeWindow{start: window[0], end: window[1]})
+		}
+	}
+	switch snap.ISOWeekParity {
+	case oddWeeks.String():
+		j.weekParity = oddWeeks
+	case evenWeeks.String():
+		j.weekParity = evenWeeks
+	}
+	j.jobFunc = snap.Func
+	j.funcs[snap.Func] = jobFun
+	j.fparams[snap.Func] = append([]interface{}{}, snap.Params...)
+	j.name = snap.Name
+	j.tags = append([]string{}, snap.Tags...)
+	for key, value := range snap.Labels {
+		j.labels[key] = value
+	}
+	j.runConfig = runConfig{
+		finiteRuns:         snap.FiniteRuns,
+		maxRuns:            snap.MaxRuns,
+		maxDuration:        snap.MaxDuration,
+		mode:               snap.Mode,
+		removeAfterLastRun: snap.RemoveAfterLastRun,
+		sync:               snap.Sync,
+	}
+	j.runCount = snap.RunCount
+	j.lastRun = snap.LastRun
+	j.lastSuccessfulRun = snap.LastSuccessfulRun
+	j.nextRun = snap.NextRun
+	return j, nil
+}
+
+// Export returns the snapshots of all the Jobs in the Scheduler so that
+// the schedule can be persisted and later restored with Import
+func (s *Scheduler) Export() []JobSnapshot {
+	jobs := s.Jobs()
+	snaps := make([]JobSnapshot, 0, len(jobs))
+	for _, job := range jobs {
+		snaps = append(snaps, job.Snapshot())
+	}
+	return snaps
+}
+
+// Import adds the Jobs described by the snapshots to the Scheduler.
+// Job functions are resolved from the registry by the name found in
+// JobSnapshot.Func. No Job is added if any of the functions is missing.
+//
+// Note that params which went through an encoding such as JSON may not
+// keep their original types (e.g. an int becomes a float64) and must
+// still match the job function's signature.
+func (s *Scheduler) Import(snaps []JobSnapshot, registry map[string]interface{}) error {
+	jobs := make([]*Job, 0, len(snaps))
+	for _, snap := range snaps {
+		job, err := newJobFromSnapshot(snap, registry)
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, job)
+	}
+
+	s.setJobs(append(s.Jobs(), jobs...))
+	if s.IsRunning() {
+		for _, job := range jobs {
+			s.scheduleNextRun(job)
+		}
+	}
+	return nil
+}
+
+// SchedulerState is the state of the Jobs of a stopped Scheduler, as returned
+// by Stop. Jobs holds their serializable snapshots
+type SchedulerState struct {
+	Jobs []JobSnapshot `json:"jobs"`
+	jobs []*Job
+}
+
+func (s *Scheduler) state() SchedulerState {
+	jobs := append([]*Job{}, s.Jobs()...)
+	state := SchedulerState{Jobs: make([]JobSnapshot, 0, len(jobs)), jobs: jobs}
+	for _, job := range jobs {
+		state.Jobs = append(state.Jobs, job.Snapshot())
+	}
+	return state
+}
+
+// Resume restores the Jobs of the Scheduler as they were in the state returned
+// by Stop, including their order, run counts and next runs, and starts the
+// Scheduler without rescheduling them. Within the same process the Jobs
+// themselves are restored. A state which went through an encoding such as JSON
+// only holds the snapshots, from which the Jobs are rebuilt as by Import,
+// their functions resolved from the registry. Nothing is restored on error
+func (s *Scheduler) Resume(state SchedulerState, registry map[string]interface{}) (chan struct{}, error) {
+	jobs := state.jobs
+	if len(jobs) != len(state.Jobs) {
+		jobs = make([]*Job, 0, len(state.Jobs))
+		for _, snap := range state.Jobs {
+			job, err := newJobFromSnapshot(snap, registry)
+			if err != nil {
+				return nil, err
+			}
+			jobs = append(jobs, job)
+		}
+	} else {
+		for i, job := range jobs {
+			snap := state.Jobs[i]
+			job.setRunCount(snap.RunCount)
+			job.setLastRun(snap.LastRun)
+			job.setLastSuccessfulRun(snap.LastSuccessfulRun)
+			job.setNextRun(snap.NextRun)
+		}
+	}
+	s.setJobs(append([]*Job{}, jobs...))
+	return s.start(false), nil
+}
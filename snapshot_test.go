@@ -0,0 +1,189 @@
+package gocron
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_ExportImport(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	secondsJob, err := s.Every(5).Seconds().Do(task)
+	require.NoError(t, err)
+	secondsJob.Tag("seconds")
+	secondsJob.SetLabel("env", "prod")
+	secondsJob.LimitRunsTo(10)
+	secondsJob.SingletonMode()
+	secondsJob.setRunCount(3)
+	lastRun := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	secondsJob.setLastRun(lastRun)
+	secondsJob.setNextRun(lastRun.Add(5 * time.Second))
+
+	weekdayJob, err := s.Every(1).Monday().At("10:30").Do(func(name string) {}, "hello")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(s.Export())
+	require.NoError(t, err)
+
+	var snaps []JobSnapshot
+	require.NoError(t, json.Unmarshal(data, &snaps))
+
+	registry := map[string]interface{}{
+		getFunctionName(task):      task,
+		weekdayJob.Snapshot().Func: func(name string) {},
+	}
+	restored := NewScheduler(time.UTC)
+	require.NoError(t, restored.Import(snaps, registry))
+	require.Equal(t, 2, restored.Len())
+
+	got := restored.Jobs()[0]
+	assert.Equal(t, seconds, got.unit)
+	assert.Equal(t, jobInterval(5), got.interval)
+	assert.Equal(t, []string{"seconds"}, got.Tags())
+	assert.Equal(t, map[string]string{"env": "prod"}, got.Labels())
+	assert.Equal(t, 3, got.RunCount())
+	assert.True(t, lastRun.Equal(got.LastRun()))
+	assert.True(t, lastRun.Add(5*time.Second).Equal(got.NextRun()))
+	assert.True(t, got.IsFiniteRuns())
+	assert.Equal(t, 10, got.MaxRuns())
+	assert.Equal(t, SingletonMode, got.runConfig.mode)
+
+	got = restored.Jobs()[1]
+	weekday, err := got.Weekday()
+	require.NoError(t, err)
+	assert.Equal(t, time.Monday, weekday)
+	assert.Equal(t, "10:30", got.ScheduledAtTime())
+	assert.False(t, got.getStartsImmediately())
+	assert.Equal(t, []interface{}{"hello"}, got.Params())
+	got.run()
+	assert.NoError(t, got.Err())
+}
+
+func TestScheduler_ImportMissingFunc(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	_, err := s.Every(1).Minute().Do(task)
+	require.NoError(t, err)
+
+	restored := NewScheduler(time.UTC)
+	err = restored.Import(s.Export(), map[string]interface{}{})
+	assert.Equal(t, ErrFuncNotRegistered, err)
+	assert.Zero(t, restored.Len())
+}
+
+func TestJob_GetSchedule(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	job, err := s.Every(2).Monday().Thursday().At("09:00", "17:30:15").Jitter(time.Minute).Do(task)
+	require.NoError(t, err)
+	job.run()
+	assert.Equal(t, Schedule{
+		Interval: 2,
+		Unit:     "weeks",
+		AtTimes:  []string{"09:00", "17:30:15"},
+		Weekdays: []time.Weekday{time.Monday, time.Thursday},
+	}, job.GetSchedule())
+
+	job, err = s.Every(1).DaysOfMonth(15, 1).Do(task)
+	require.NoError(t, err)
+	assert.Equal(t, Schedule{Interval: 1, Unit: "months", DaysOfMonth: []int{1, 15}}, job.GetSchedule())
+
+	schedule := job.GetSchedule()
+	schedule.DaysOfMonth[0] = 2
+	assert.Equal(t, []int{1, 15}, job.GetSchedule().DaysOfMonth, "the schedule should be a copy")
+}
+
+func TestScheduler_Diff(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	report, err := s.Every(1).Monday().Friday().At("09:00").Do(task)
+	require.NoError(t, err)
+	report.SetName("report")
+	cleanup, err := s.Every(1).Hour().Do(task)
+	require.NoError(t, err)
+	cleanup.SetName("cleanup")
+	legacy, err := s.Every(1).Day().Do(task)
+	require.NoError(t, err)
+	legacy.SetName("legacy")
+	_, err = s.Every(1).Minute().Do(task) // unnamed jobs are ignored
+	require.NoError(t, err)
+
+	desired := []Schedule{
+		{Name: "report", Interval: 1, Unit: "weeks", AtTimes: []string{"9:00"}, Weekdays: []time.Weekday{time.Friday, time.Monday}},
+		{Name: "cleanup", Interval: 2, Unit: "hours"},
+		{Name: "backup", Interval: 1, Unit: "days", AtTimes: []string{"03:00"}},
+		{Interval: 1, Unit: "minutes"}, // unnamed schedules are ignored
+	}
+	toAdd, toUpdate, toRemove := s.Diff(desired)
+	assert.Equal(t, []Schedule{desired[2]}, toAdd)
+	assert.Equal(t, []Schedule{desired[1]}, toUpdate)
+	assert.Equal(t, []Schedule{legacy.GetSchedule()}, toRemove)
+
+	toAdd, toUpdate, toRemove = s.Diff([]Schedule{report.GetSchedule(), cleanup.GetSchedule(), legacy.GetSchedule()})
+	assert.Empty(t, toAdd)
+	assert.Empty(t, toUpdate)
+	assert.Empty(t, toRemove)
+}
+
+func TestScheduler_StopResume(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	first, err := s.Every(1).Hour().Do(task)
+	require.NoError(t, err)
+	second, err := s.Every(1).Day().At("10:00").Do(task)
+	require.NoError(t, err)
+
+	s.StartAsync()
+	first.setRunCount(4)
+	nextRuns := []time.Time{first.NextRun(), second.NextRun()}
+
+	state := s.Stop()
+	require.False(t, s.IsRunning())
+	require.Len(t, state.Jobs, 2)
+	assert.Equal(t, 4, state.Jobs[0].RunCount)
+
+	// alter the scheduler while it's stopped
+	s.Clear()
+	first.setRunCount(0)
+	first.setNextRun(time.Time{})
+
+	_, err = s.Resume(state, nil)
+	require.NoError(t, err)
+	defer s.Stop()
+	assert.True(t, s.IsRunning())
+	assert.Equal(t, []*Job{first, second}, s.Jobs())
+	assert.Equal(t, 4, first.RunCount())
+	assert.Equal(t, nextRuns, []time.Time{first.NextRun(), second.NextRun()})
+}
+
+func TestScheduler_StopResumeJSON(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	first, err := s.Every(1).Hour().Do(task)
+	require.NoError(t, err)
+	_, err = s.Every(1).Day().At("10:00").Do(task)
+	require.NoError(t, err)
+
+	s.StartAsync()
+	first.setRunCount(4)
+	nextRun := first.NextRun()
+
+	data, err := json.Marshal(s.Stop())
+	require.NoError(t, err)
+	var state SchedulerState
+	require.NoError(t, json.Unmarshal(data, &state))
+
+	restored := NewScheduler(time.UTC)
+	_, err = restored.Resume(state, map[string]interface{}{})
+	assert.Equal(t, ErrFuncNotRegistered, err)
+	assert.False(t, restored.IsRunning())
+	assert.Zero(t, restored.Len())
+
+	_, err = restored.Resume(state, map[string]interface{}{getFunctionName(task): task})
+	require.NoError(t, err)
+	defer restored.Stop()
+	assert.True(t, restored.IsRunning())
+	require.Equal(t, 2, restored.Len())
+	got := restored.Jobs()[0]
+	assert.Equal(t, 4, got.RunCount())
+	assert.True(t, nextRun.Equal(got.NextRun()))
+	assert.Equal(t, days, restored.Jobs()[1].unit)
+}
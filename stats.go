@@ -0,0 +1,38 @@
+package gocron
+
+import "time"
+
+// SchedulerStats aggregates the state of the jobs of a Scheduler, as
+// returned by Stats
+type SchedulerStats struct {
+	Jobs        int       // number of jobs
+	RunningJobs int       // jobs with a run in progress
+	Runs        int       // runs of the jobs, see Job.RunCount
+	Errors      int       // runs which returned an error, see Job.ErrorCount
+	SkippedRuns int       // triggers which did not run their job, see Job.SkippedRuns
+	NextRun     time.Time // earliest next run of the jobs which aren't paused, zero if none
+}
+
+// Stats returns the aggregated state of the jobs, read under the Scheduler's
+// read lock, e.g. for a metrics exporter to pull it in a single call. The
+// counts are the ones of the current jobs, which start again from zero for
+// the jobs reset with ResetRunCount
+func (s *Scheduler) Stats() SchedulerStats {
+	var stats SchedulerStats
+	s.ForEach(func(job *Job) bool {
+		job.RLock()
+		defer job.RUnlock()
+		stats.Jobs++
+		if job.activeRuns > 0 {
+			stats.RunningJobs++
+		}
+		stats.Runs += job.runCount
+		stats.Errors += job.errorCount
+		stats.SkippedRuns += job.skippedRuns
+		if !job.paused && !job.nextRun.IsZero() && (stats.NextRun.IsZero() || job.nextRun.Before(stats.NextRun)) {
+			stats.NextRun = job.nextRun
+		}
+		return true
+	})
+	return stats
+}
@@ -0,0 +1,57 @@
+package gocron
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Stats(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 10, 0, 0, 0, time.UTC)
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+	assert.Equal(t, SchedulerStats{}, s.Stats())
+
+	ok, err := s.Every(1).Minute().Do(func() {})
+	require.NoError(t, err)
+	failing, err := s.Every(1).Hour().Do(func() error { return errors.New("failed") })
+	require.NoError(t, err)
+	paused, err := s.Every(1).Second().Do(func() {})
+	require.NoError(t, err)
+	for _, job := range s.Jobs() {
+		job.Sync()
+		job.setLastRun(now)
+		s.scheduleNextRun(job)
+	}
+	paused.Pause()
+
+	require.NoError(t, s.run(ok))
+	require.NoError(t, s.run(ok))
+	require.NoError(t, s.run(failing))
+	paused.skip(SkipReasonPaused)
+
+	assert.Equal(t, SchedulerStats{
+		Jobs:        3,
+		Runs:        3,
+		Errors:      1,
+		SkippedRuns: 1,
+		NextRun:     now.Add(time.Minute),
+	}, s.Stats())
+
+	t.Run("running", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		release := make(chan struct{})
+		f, started, _, _ := slowJob(release)
+		job, err := s.Every(1).Second().Do(f)
+		require.NoError(t, err)
+		require.NoError(t, s.run(job))
+		<-started
+		assert.Equal(t, 1, s.Stats().RunningJobs)
+		close(release)
+		s.runningJobs.Wait()
+		assert.Equal(t, 0, s.Stats().RunningJobs)
+	})
+}
@@ -0,0 +1,43 @@
+package gocron
+
+import "time"
+
+// Strategy computes the next run of a Job from the time it last ran, e.g. to
+// follow a market calendar. The Job's jitter, daily window, blackout windows
+// and excluded dates are still applied to the time it returns
+type Strategy interface {
+	Next(job *Job, from time.Time) time.Time
+}
+
+// intervalStrategy is the default Strategy, running the Jobs at their interval
+// and, when set, at their specific times and days
+type intervalStrategy struct {
+	s *Scheduler
+}
+
+func (st intervalStrategy) Next(job *Job, from time.Time) time.Time {
+	return from.Add(st.s.durationToNextRunFrom(job, from))
+}
+
+// SetStrategy replaces the Strategy used to compute the next run of the Jobs
+// which don't have a NextRunFunc. A nil Strategy restores the default one
+func (s *Scheduler) SetStrategy(strategy Strategy) {
+	s.strategyMutex.Lock()
+	defer s.strategyMutex.Unlock()
+	s.strategy = strategy
+}
+
+// IntervalStrategy returns the default Strategy of the Scheduler, e.g. for a
+// custom Strategy to fall back to
+func (s *Scheduler) IntervalStrategy() Strategy {
+	return intervalStrategy{s: s}
+}
+
+func (s *Scheduler) getStrategy() Strategy {
+	s.strategyMutex.RLock()
+	defer s.strategyMutex.RUnlock()
+	if s.strategy == nil {
+		return s.IntervalStrategy()
+	}
+	return s.strategy
+}
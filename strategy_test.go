@@ -0,0 +1,67 @@
+package gocron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// weekdaysStrategy runs the jobs at their interval, skipping the weekends
+type weekdaysStrategy struct {
+	fallback Strategy
+}
+
+func (st weekdaysStrategy) Next(job *Job, from time.Time) time.Time {
+	next := st.fallback.Next(job, from)
+	for next.Weekday() == time.Saturday || next.Weekday() == time.Sunday {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+func TestScheduler_SetStrategy(t *testing.T) {
+	// January 3rd 2020 is a Friday
+	friday := time.Date(2020, time.January, 3, 9, 0, 0, 0, time.UTC)
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return friday }}
+	job, err := s.Every(1).Day().At("09:00").Do(task)
+	require.NoError(t, err)
+	job.setLastRun(friday)
+
+	s.scheduleNextRun(job)
+	assert.Equal(t, friday.AddDate(0, 0, 1), job.NextRun(), "the default strategy runs the job the next day")
+
+	s.SetStrategy(weekdaysStrategy{fallback: s.IntervalStrategy()})
+	s.scheduleNextRun(job)
+	assert.Equal(t, friday.AddDate(0, 0, 3), job.NextRun(), "the custom strategy skips the weekend")
+
+	s.SetStrategy(nil)
+	s.scheduleNextRun(job)
+	assert.Equal(t, friday.AddDate(0, 0, 1), job.NextRun())
+}
+
+func TestScheduler_StrategyReadsJobConfig(t *testing.T) {
+	now := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	s := NewScheduler(time.UTC)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+	job, err := s.Every(3).Hours().Do(task)
+	require.NoError(t, err)
+	job.setLastRun(now)
+
+	var got []interface{}
+	s.SetStrategy(strategyFunc(func(job *Job, from time.Time) time.Time {
+		got = append(got, job.Interval(), job.Unit())
+		return from.Add(time.Minute)
+	}))
+	s.scheduleNextRun(job)
+	assert.Equal(t, []interface{}{uint64(3), "hours"}, got)
+	assert.Equal(t, now.Add(time.Minute), job.NextRun())
+}
+
+type strategyFunc func(job *Job, from time.Time) time.Time
+
+func (f strategyFunc) Next(job *Job, from time.Time) time.Time {
+	return f(job, from)
+}
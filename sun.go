@@ -0,0 +1,99 @@
+package gocron
+
+import (
+	"math"
+	"time"
+)
+
+// sunEvent is a daily solar event a Job can be scheduled at
+type sunEvent int
+
+const (
+	sunrise sunEvent = iota
+	sunset
+)
+
+const (
+	julianUnixEpoch = 2440587.5 // Julian day of the Unix epoch
+	julian2000      = 2451545.0 // Julian day of J2000.0
+	earthTilt       = 23.4397   // obliquity of the ecliptic, in degrees
+	sunAltitude     = -0.833    // altitude of the sun's centre at rise and set, in degrees
+)
+
+// maxSunSearchDays bounds the days searched for the next solar event, as
+// there are none during a polar night or day
+const maxSunSearchDays = 366
+
+// AtSunrise schedules the Job at sunrise each day at the given latitude and
+// longitude, in degrees, north and east being positive. The time is
+// recomputed after each run as it shifts along the year. It replaces any
+// NextRunFunc set on the Job
+func (j *Job) AtSunrise(lat, lon float64) *Job {
+	j.atSunEvent(sunrise, lat, lon)
+	return j
+}
+
+// AtSunset schedules the Job at sunset each day at the given latitude and
+// longitude, in degrees, north and east being positive. The time is
+// recomputed after each run as it shifts along the year. It replaces any
+// NextRunFunc set on the Job
+func (j *Job) AtSunset(lat, lon float64) *Job {
+	j.atSunEvent(sunset, lat, lon)
+	return j
+}
+
+func (j *Job) atSunEvent(event sunEvent, lat, lon float64) {
+	j.Lock()
+	defer j.Unlock()
+	j.startsImmediately = false
+	j.nextRunFunc = func(last time.Time) time.Time {
+		return nextSunEvent(event, lat, lon, last)
+	}
+}
+
+// nextSunEvent returns the first solar event after t at the given location,
+// in t's location, or the zero time if there is none within a year
+func nextSunEvent(event sunEvent, lat, lon float64, t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	for i := 0; i <= maxSunSearchDays; i++ {
+		at, ok := sunEventOn(event, lat, lon, day.AddDate(0, 0, i))
+		if ok && at.After(t) {
+			return at.In(t.Location())
+		}
+	}
+	return time.Time{}
+}
+
+// sunEventOn computes the solar event on the given UTC day with the sunrise
+// equation, returning false if the sun doesn't rise or set that day
+func sunEventOn(event sunEvent, lat, lon float64, day time.Time) (time.Time, bool) {
+	julianDay := float64(day.Unix())/86400 + julianUnixEpoch
+	n := math.Ceil(julianDay - julian2000 + 0.0008)
+	meanSolarTime := n - lon/360
+
+	anomaly := math.Mod(357.5291+0.98560028*meanSolarTime, 360)
+	m := radians(anomaly)
+	center := 1.9148*math.Sin(m) + 0.0200*math.Sin(2*m) + 0.0003*math.Sin(3*m)
+	longitude := radians(math.Mod(anomaly+center+180+102.9372, 360))
+	transit := julian2000 + meanSolarTime + 0.0053*math.Sin(m) - 0.0069*math.Sin(2*longitude)
+
+	declination := math.Asin(math.Sin(longitude) * math.Sin(radians(earthTilt)))
+	phi := radians(lat)
+	cosHourAngle := (math.Sin(radians(sunAltitude)) - math.Sin(phi)*math.Sin(declination)) /
+		(math.Cos(phi) * math.Cos(declination))
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, false
+	}
+	hourAngle := math.Acos(cosHourAngle) * 180 / math.Pi
+
+	at := transit + hourAngle/360
+	if event == sunrise {
+		at = transit - hourAngle/360
+	}
+	seconds := (at - julianUnixEpoch) * 86400
+	return time.Unix(0, int64(seconds*float64(time.Second))).UTC(), true
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
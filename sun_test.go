@@ -0,0 +1,73 @@
+package gocron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextSunEvent(t *testing.T) {
+	const lat, lon = 48.8566, 2.3522 // Paris
+
+	testCases := []struct {
+		description string
+		event       sunEvent
+		day         time.Time
+		expected    time.Time
+	}{
+		{"sunrise at summer solstice", sunrise, time.Date(2020, time.June, 21, 0, 0, 0, 0, time.UTC), time.Date(2020, time.June, 21, 3, 47, 0, 0, time.UTC)},
+		{"sunrise at winter solstice", sunrise, time.Date(2020, time.December, 21, 0, 0, 0, 0, time.UTC), time.Date(2020, time.December, 21, 7, 42, 0, 0, time.UTC)},
+		{"sunset at summer solstice", sunset, time.Date(2020, time.June, 21, 0, 0, 0, 0, time.UTC), time.Date(2020, time.June, 21, 19, 58, 0, 0, time.UTC)},
+		{"sunset at winter solstice", sunset, time.Date(2020, time.December, 21, 0, 0, 0, 0, time.UTC), time.Date(2020, time.December, 21, 15, 56, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			at := nextSunEvent(tc.event, lat, lon, tc.day)
+			assert.WithinDuration(t, tc.expected, at, 3*time.Minute)
+		})
+	}
+
+	t.Run("after today's event", func(t *testing.T) {
+		afterSunrise := time.Date(2020, time.June, 21, 12, 0, 0, 0, time.UTC)
+		at := nextSunEvent(sunrise, lat, lon, afterSunrise)
+		assert.Equal(t, 22, at.Day())
+	})
+
+	t.Run("polar night", func(t *testing.T) {
+		const svalbardLat, svalbardLon = 78.2232, 15.6267
+		midwinter := time.Date(2020, time.December, 21, 0, 0, 0, 0, time.UTC)
+		at := nextSunEvent(sunrise, svalbardLat, svalbardLon, midwinter)
+		assert.True(t, at.After(midwinter.AddDate(0, 1, 0)))
+		assert.True(t, at.Before(midwinter.AddDate(0, 3, 0)))
+	})
+}
+
+func TestJob_AtSunrise(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	now := time.Date(2020, time.March, 1, 12, 0, 0, 0, loc)
+	s := NewScheduler(loc)
+	s.time = fakeTime{onNow: func(location *time.Location) time.Time { return now }}
+
+	job, err := s.Every(1).Day().Do(func() {})
+	require.NoError(t, err)
+	job.AtSunrise(48.8566, 2.3522)
+	s.scheduleNextRun(job)
+
+	var sunrises []time.Time
+	for i := 0; i < 3; i++ {
+		next := job.NextRun()
+		assert.Equal(t, loc, next.Location())
+		assert.True(t, next.After(now))
+		sunrises = append(sunrises, next)
+		now = next.Add(time.Second)
+		job.setLastRun(now)
+		s.scheduleNextRun(job)
+	}
+	assert.Equal(t, 2, sunrises[0].Day())
+	assert.Equal(t, 3, sunrises[1].Day())
+	assert.True(t, sunrises[1].Sub(sunrises[0]) < 24*time.Hour, "sunrise gets earlier in March")
+}
@@ -0,0 +1,48 @@
+package gocron
+
+import "context"
+
+// Tracer starts the spans wrapping the runs of the jobs, keeping the
+// package free of a tracing dependency. An OpenTelemetry trace.Tracer
+// can be used through a small adapter:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, gocron.Span) {
+//		ctx, span := t.tracer.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ span trace.Span }
+//
+//	func (s otelSpan) RecordError(err error) { s.span.RecordError(err) }
+//	func (s otelSpan) End()                  { s.span.End() }
+type Tracer interface {
+	// Start starts a span named spanName, child of the span in ctx if any,
+	// and returns the span and a context holding it
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer
+type Span interface {
+	// RecordError records the error the run returned
+	RecordError(err error)
+	// End ends the span once the run completed
+	End()
+}
+
+// SetTracer sets the Tracer starting a span for each run of the jobs, named
+// after the Job's name or else its function. The span is propagated through
+// the context of the run, and records the error of the run. A nil Tracer
+// disables the tracing
+func (s *Scheduler) SetTracer(tracer Tracer) {
+	s.tracerMutex.Lock()
+	defer s.tracerMutex.Unlock()
+	s.tracer = tracer
+}
+
+func (s *Scheduler) getTracer() Tracer {
+	s.tracerMutex.RLock()
+	defer s.tracerMutex.RUnlock()
+	return s.tracer
+}
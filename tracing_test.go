@@ -0,0 +1,67 @@
+package gocron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type spanKey struct{}
+
+type mockSpan struct {
+	name  string
+	err   error
+	ended bool
+}
+
+func (s *mockSpan) RecordError(err error) { s.err = err }
+func (s *mockSpan) End()                  { s.ended = true }
+
+type mockTracer struct {
+	mu    sync.Mutex
+	spans []*mockSpan
+}
+
+func (t *mockTracer) Start(ctx context.Context, spanName string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &mockSpan{name: spanName}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func TestScheduler_SetTracer(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	tracer := &mockTracer{}
+	s.SetTracer(tracer)
+
+	errFailed := errors.New("failed")
+	var propagated interface{}
+	failing, err := s.Every(1).Hour().Do(func(ctx context.Context) error {
+		propagated = ctx.Value(spanKey{})
+		return errFailed
+	})
+	require.NoError(t, err)
+	failing.Sync()
+	failing.SetName("failing")
+	succeeding, err := s.Every(1).Hour().Do(task)
+	require.NoError(t, err)
+	succeeding.Sync()
+
+	require.NoError(t, s.run(failing))
+	require.NoError(t, s.run(succeeding))
+
+	require.Len(t, tracer.spans, 2)
+	assert.Equal(t, &mockSpan{name: "failing", err: errFailed, ended: true}, tracer.spans[0])
+	assert.Equal(t, tracer.spans[0], propagated, "the span should be propagated through the context")
+	assert.Equal(t, &mockSpan{name: "github.com/go-co-op/gocron.task", ended: true}, tracer.spans[1])
+
+	s.SetTracer(nil)
+	require.NoError(t, s.run(succeeding))
+	assert.Len(t, tracer.spans, 2)
+}
@@ -0,0 +1,63 @@
+package gocron
+
+import (
+	"context"
+	"time"
+)
+
+// WeightedFunc is one of the functions a Job added with DoWeighted picks from,
+// called with its Params on the runs it's drawn for, in proportion to its Weight
+type WeightedFunc struct {
+	Func   interface{}
+	Params []interface{}
+	Weight int
+}
+
+// DoWeighted specifies the functions to pick from each time the Job runs,
+// e.g. for sampling tasks. Each run calls one of them, drawn at random with
+// a probability proportional to its weight, using the source of randomness
+// set with SetRandSource. It returns ErrInvalidWeights if there are no
+// entries or one of them has no positive weight
+func (s *Scheduler) DoWeighted(entries []WeightedFunc) (*Job, error) {
+	total := 0
+	for _, entry := range entries {
+		if entry.Weight <= 0 {
+			total = 0
+			break
+		}
+		total += entry.Weight
+	}
+	if total <= 0 {
+		j := s.getCurrentJob()
+		j.setErr(ErrInvalidWeights)
+		s.RemoveByReference(j)
+		return nil, ErrInvalidWeights
+	}
+	for _, entry := range entries {
+		if err := validateJobFunc(entry.Func, entry.Params); err != nil {
+			j := s.getCurrentJob()
+			j.setErr(err)
+			s.RemoveByReference(j)
+			return nil, err
+		}
+	}
+	entries = append([]WeightedFunc(nil), entries...)
+	return s.Do(func(ctx context.Context, at time.Time) error {
+		entry := s.pickWeighted(entries, total)
+		return callJobFunc(ctx, at, entry.Func, entry.Params)
+	})
+}
+
+// pickWeighted draws one of the entries, whose weights add up to total
+func (s *Scheduler) pickWeighted(entries []WeightedFunc, total int) WeightedFunc {
+	s.randMutex.Lock()
+	n := s.rand.Intn(total)
+	s.randMutex.Unlock()
+	for _, entry := range entries {
+		if n < entry.Weight {
+			return entry
+		}
+		n -= entry.Weight
+	}
+	return entries[len(entries)-1]
+}
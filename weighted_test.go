@@ -0,0 +1,42 @@
+package gocron
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_DoWeighted(t *testing.T) {
+	s := NewScheduler(time.UTC)
+	s.SetRandSource(rand.NewSource(1))
+	counts := make(map[string]int)
+	record := func(name string) { counts[name]++ }
+	job, err := s.Every(1).Second().DoWeighted([]WeightedFunc{
+		{Func: record, Params: []interface{}{"a"}, Weight: 1},
+		{Func: record, Params: []interface{}{"b"}, Weight: 3},
+	})
+	require.NoError(t, err)
+	job.Sync()
+
+	const runs = 4000
+	for i := 0; i < runs; i++ {
+		require.NoError(t, s.run(job))
+	}
+	assert.Equal(t, runs, counts["a"]+counts["b"])
+	assert.InDelta(t, 0.25, float64(counts["a"])/runs, 0.03)
+	assert.InDelta(t, 0.75, float64(counts["b"])/runs, 0.03)
+
+	t.Run("invalid entries", func(t *testing.T) {
+		s := NewScheduler(time.UTC)
+		_, err := s.Every(1).Second().DoWeighted(nil)
+		assert.Equal(t, ErrInvalidWeights, err)
+		_, err = s.Every(1).Second().DoWeighted([]WeightedFunc{{Func: task, Weight: 1}, {Func: task}})
+		assert.Equal(t, ErrInvalidWeights, err)
+		_, err = s.Every(1).Second().DoWeighted([]WeightedFunc{{Func: "not a func", Weight: 1}})
+		assert.Equal(t, ErrNotAFunction, err)
+		assert.Empty(t, s.Jobs())
+	})
+}